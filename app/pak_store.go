@@ -2,14 +2,20 @@ package main
 
 import (
 	_ "embed"
-	"os"
+	"fmt"
 	"time"
 
 	_ "github.com/UncleJunVIP/certifiable"
 	gaba "github.com/UncleJunVIP/gabagool/pkg/gabagool"
+	"github.com/UncleJunVIP/gabagool/pkg/gabagool/constants"
 	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
 	"github.com/UncleJunVIP/nextui-pak-store/database"
+	"github.com/UncleJunVIP/nextui-pak-store/diagnostics"
+	"github.com/UncleJunVIP/nextui-pak-store/httpclient"
+	"github.com/UncleJunVIP/nextui-pak-store/mirrors"
 	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/navigation"
+	"github.com/UncleJunVIP/nextui-pak-store/settings"
 	"github.com/UncleJunVIP/nextui-pak-store/state"
 	"github.com/UncleJunVIP/nextui-pak-store/ui"
 	"github.com/UncleJunVIP/nextui-pak-store/utils"
@@ -25,25 +31,120 @@ func init() {
 		LogFilename:    "pak_store.log",
 	})
 
+	var pak models.Pak
+	if err := utils.ParseJSONFile("pak.json", &pak); err != nil {
+		common.GetLoggerInstance().Warn("Unable to read Pak Store version", "error", err)
+	}
+
+	state.PakStoreVersion = pak.Version
+
+	httpclient.Configure(httpclient.Options{
+		Version: pak.Version,
+		Logger:  common.GetLoggerInstance(),
+	})
+
+	database.Init()
+
+	if err := settings.Load(); err != nil {
+		common.GetLoggerInstance().Error("Unable to load settings, using defaults", "error", err)
+	}
+
+	if settings.Get().CleanupTempFiles {
+		utils.RemoveTempFiles()
+	}
+
 	sf, err := gaba.ProcessMessage("",
 		gaba.ProcessMessageOptions{Image: "resources/splash.png", ImageWidth: 1024, ImageHeight: 768}, func() (interface{}, error) {
-			time.Sleep(1250 * time.Millisecond)
-			return utils.FetchStorefront()
+			time.Sleep(settings.MessageDelay(1250 * time.Millisecond))
+			return mirrors.FetchStorefront()
 		})
 
 	if err != nil {
-		gaba.ConfirmationMessage("Could not load the Storefront!\nMake sure you are connected to Wi-Fi.\nIf this issue persists, check the logs.", []gaba.FooterHelpItem{
-			{ButtonName: "B", HelpText: "Quit"},
-		}, gaba.MessageOptions{})
-		defer gaba.Close()
-		common.LogStandardFatal("Could not load Storefront!", err)
+		appState = state.NewAppState(recoverStorefront(err))
+		return
 	}
 
-	database.Init()
-
 	appState = state.NewAppState(sf.Result.(models.Storefront))
 }
 
+// recoverStorefront diagnoses why the storefront could not be loaded. A wrong clock can be
+// fixed on the spot, after which the storefront is fetched again. Anything else is fatal.
+func recoverStorefront(err error) models.Storefront {
+	logger := common.GetLoggerInstance()
+
+	res, _ := gaba.ProcessMessage("Checking your connection...", gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+		return diagnostics.Run(err), nil
+	})
+
+	report, ok := res.Result.(diagnostics.Report)
+	if !ok {
+		report = diagnostics.Report{Cause: err}
+	}
+
+	logger.Error("Could not load Storefront",
+		"error", err,
+		"diagnosis", report.Message(),
+		"probes", report.Probes)
+
+	if report.Kind == diagnostics.Kinds.ClockSkew && report.Clock.Valid() && diagnostics.CanSetClock() {
+		fix, _ := gaba.ConfirmationMessage(fmt.Sprintf("%s\nSet the clock to %s?",
+			report.Message(), report.Clock.Now().Local().Format("Jan 2, 2006 15:04")), []gaba.FooterHelpItem{
+			{ButtonName: "B", HelpText: "No"},
+			{ButtonName: "X", HelpText: "Set Clock"},
+		}, gaba.MessageOptions{
+			ConfirmButton: constants.VirtualButtonX,
+		})
+
+		if !fix.IsNone() {
+			if clockErr := diagnostics.SetClock(report.Clock.Now()); clockErr != nil {
+				logger.Error("Unable to set the system clock", "error", clockErr)
+			} else {
+				logger.Info("Corrected the system clock", "skew", report.Clock.Skew())
+
+				sf, retryErr := gaba.ProcessMessage("Loading the Storefront...", gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+					return mirrors.FetchStorefront()
+				})
+				if retryErr == nil {
+					return sf.Result.(models.Storefront)
+				}
+
+				err = retryErr
+				logger.Error("Could not load Storefront after correcting the clock", "error", err)
+			}
+		}
+	}
+
+	if settings.Get().OfflineBehavior == models.OfflineBehaviors.UseLastStorefront {
+		if sf, snapshotErr := utils.LoadStorefrontSnapshot(); snapshotErr == nil {
+			logger.Warn("Using the last storefront that loaded", "generated_at", sf.GeneratedAt)
+
+			gaba.ProcessMessage(fmt.Sprintf("Could not load the Storefront!\n%s\nUsing the copy from %s.",
+				report.Message(), sf.GeneratedAt.Local().Format("Jan 2, 2006")), gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+				time.Sleep(settings.MessageDelay(3 * time.Second))
+				return nil, nil
+			})
+
+			return sf
+		}
+	}
+
+	details, _ := gaba.ConfirmationMessage("Could not load the Storefront!\n"+report.Message()+"\nIf this issue persists, check the logs.", []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Quit"},
+		{ButtonName: "X", HelpText: "Details"},
+	}, gaba.MessageOptions{
+		ConfirmButton: constants.VirtualButtonX,
+	})
+
+	if !details.IsNone() {
+		ui.InitDiagnosticsScreen(report).Draw()
+	}
+
+	defer gaba.Close()
+	common.LogStandardFatal("Could not load Storefront!", err)
+
+	return models.Storefront{}
+}
+
 func cleanup() {
 	database.CloseDB()
 	gaba.Close()
@@ -56,123 +157,45 @@ func main() {
 
 	logger.Info("Starting Pak Store")
 
-	var screen models.Screen
-	screen = ui.InitMainMenu(appState)
-
-	for {
-		res, code, _ := screen.Draw()
-
-		if code == 23 {
+	if len(appState.AutoUpdatesPending) > 0 {
+		if ui.RunAutoUpdates(appState.AutoUpdatesPending) {
 			gaba.ProcessMessage("Pak Store Updated! Exiting...", gaba.ProcessMessageOptions{}, func() (interface{}, error) {
-				time.Sleep(3 * time.Second)
+				time.Sleep(settings.MessageDelay(3 * time.Second))
 				return nil, nil
 			})
-			os.Exit(0)
+			return
 		}
 
-		switch screen.Name() {
-		case models.ScreenNames.MainMenu:
-			switch code {
-			case 0:
-				switch res.(string) {
-				case "Browse":
-					screen = ui.InitBrowseScreen(appState)
-				case "Available Updates":
-					screen = ui.InitUpdatesScreen(appState)
-				case "Manage Installed":
-					screen = ui.InitManageInstalledScreen(appState)
-				}
-			case 4:
-				appState = appState.Refresh()
-				screen = ui.InitMainMenu(appState)
-			case 1, 2:
-				os.Exit(0)
-			}
-
-		case models.ScreenNames.Browse:
-			switch code {
-			case 0:
-				state.LastSelectedIndex = 0
-				state.LastSelectedPosition = 0
-				screen = ui.InitPakList(appState, res.(string))
-			case 1, 2:
-				screen = ui.InitMainMenu(appState)
-			}
-
-		case models.ScreenNames.PakList:
-			switch code {
-			case 0:
-				screen = ui.InitPakInfoScreen([]models.Pak{res.(models.Pak)}, screen.(ui.PakList).Category, false, false)
-			case 1, 2:
-				screen = ui.InitBrowseScreen(appState)
-			}
+		appState = appState.Refresh()
+	}
 
-		case models.ScreenNames.PakInfo:
-			switch code {
-			case 0, 1, 2, 4:
-				appState = appState.Refresh()
+	stack := navigation.NewStack(ui.InitMainMenu(appState))
 
-				if screen.(ui.PakInfoScreen).IsInstalled {
-					screen = ui.InitManageInstalledScreen(appState)
-					break
-				}
+	for {
+		screen := stack.Current()
 
-				if res.(bool) {
-					if len(appState.UpdatesAvailable) == 0 {
-						screen = ui.InitMainMenu(appState)
-						break
-					}
-
-					screen = ui.InitUpdatesScreen(appState)
-				} else {
-					if len(appState.AvailablePaks) == 0 {
-						screen = ui.InitBrowseScreen(appState)
-						break
-					}
-
-					if len(appState.BrowsePaks[screen.(ui.PakInfoScreen).Category]) == 0 {
-						screen = ui.InitBrowseScreen(appState)
-						break
-					}
-					screen = ui.InitPakList(appState, screen.(ui.PakInfoScreen).Category)
-				}
-			case -1:
-				gaba.ProcessMessage("Unable to Download Pak!", gaba.ProcessMessageOptions{ShowThemeBackground: true}, func() (interface{}, error) {
-					time.Sleep(1750 * time.Millisecond)
-					return nil, nil
-				})
-				break
-			case 12:
-			// Action confirmation cancel
-			case 33:
-				// User canceled multiple downloads
-				appState = appState.Refresh()
-				screen = ui.InitUpdatesScreen(appState)
-			case 86:
-				appState = appState.Refresh()
-				screen = ui.InitManageInstalledScreen(appState)
-			}
+		nav, err := screen.Draw()
+		if err != nil {
+			logger.Error("Screen failed", "screen", screen.Name(), "error", err)
+		}
 
-		case models.ScreenNames.Updates:
-			switch code {
-			case 0:
-				appState = appState.Refresh()
-				screen = ui.InitPakInfoScreen(res.([]models.Pak), "", true, false)
-			case 1, 2:
-				appState = appState.Refresh()
-				screen = ui.InitMainMenu(appState)
-			}
+		if nav.Action == models.NavigationActions.SelfUpdated {
+			gaba.ProcessMessage("Pak Store Updated! Exiting...", gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+				time.Sleep(settings.MessageDelay(3 * time.Second))
+				return nil, nil
+			})
+			return
+		}
 
-		case models.ScreenNames.ManageInstalled:
-			switch code {
-			case 0:
-				screen = ui.InitPakInfoScreen([]models.Pak{res.(models.Pak)}, "", false, true)
-			case 1, 2:
-				appState = appState.Refresh()
-				screen = ui.InitMainMenu(appState)
-			}
+		if !stack.Apply(nav) {
+			return
+		}
 
+		if nav.Refresh {
+			appState = appState.Refresh()
+			stack.Rebuild(func(s models.Screen) models.Screen {
+				return ui.Refresh(s, appState)
+			})
 		}
 	}
-
 }
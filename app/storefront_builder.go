@@ -1,127 +1,386 @@
 package main
 
 import (
-	"encoding/base64"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/UncleJunVIP/nextui-pak-store/forge"
+	"github.com/UncleJunVIP/nextui-pak-store/httpclient"
 	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/schema"
+	"github.com/UncleJunVIP/nextui-pak-store/storefront"
+	"github.com/UncleJunVIP/nextui-pak-store/version"
 )
 
-type GitHubContent struct {
-	Name        string `json:"name"`
-	Path        string `json:"path"`
-	Encoding    string `json:"encoding"`
-	Content     string `json:"content"`
-	DownloadUrl string `json:"download_url"`
-}
-
 func main() {
+	strict := flag.Bool("strict", false, "fail when a pak.json has unknown or mistyped fields")
+	force := flag.Bool("force", false, "publish even when the diff against the published storefront looks catastrophic")
+	maxRemoved := flag.Float64("max-removed", 0.2, "largest share of published paks that may disappear in one build")
+	published := flag.String("published", models.StorefrontJsonURL, "URL of the currently published storefront")
+	flag.Parse()
+
+	var self models.Pak
+	if data, err := os.ReadFile(models.PakJsonStub); err == nil {
+		_ = json.Unmarshal(data, &self)
+	}
+
+	httpclient.Configure(httpclient.Options{Version: self.Version})
+
 	data, err := os.ReadFile("storefront_base.json")
 	if err != nil {
 		log.Fatal("Error reading file:", err)
 	}
 
 	var sf models.Storefront
-	if err := json.Unmarshal(data, &sf); err != nil {
+	if err := schema.UnmarshalStrict(data, &sf); err != nil {
 		log.Fatal("Unable to unmarshal storefront", err)
 	}
 
 	var paks []models.Pak
 
 	for _, p := range sf.Paks {
-		repoPath := strings.ReplaceAll(p.RepoURL, models.GitHubRoot, "")
-		parts := strings.Split(repoPath, "/")
-		if len(parts) < 2 {
-			log.Fatal("Invalid repository URL format:", p.RepoURL)
+		provider, err := forge.ForRepo(p.RepoURL, p.Forge)
+		if err != nil {
+			log.Fatal("Invalid repository URL format:", p.RepoURL, err)
 		}
 
-		owner := parts[0]
-		repo := parts[1]
-
-		apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/contents/%s",
-			owner, repo, models.PakJsonStub)
-
 		pak := models.Pak{}
-		var err error
+		missingAsset := false
 
 		if !p.Disabled {
-			pak, err = fetchPakJsonFromGitHubAPI(apiURL)
+			release, err := provider.LatestRelease()
+			if err != nil {
+				log.Fatal("Unable to fetch latest release for "+p.Name+" ("+p.RepoURL+")", err)
+			}
+
+			ref := forge.TagRef(release.Tag)
+
+			var problems []error
+			pak, problems, err = fetchPakJson(provider, ref)
 			if err != nil {
 				log.Fatal("Unable to fetch pak json for "+p.Name+" ("+p.RepoURL+")", err)
 			}
+
+			for _, problem := range problems {
+				log.Println("pak.json for "+p.StorefrontName+" ("+p.RepoURL+"):", problem)
+			}
+
+			if *strict && len(problems) > 0 {
+				log.Fatal("pak.json for " + p.StorefrontName + " does not match the schema")
+			}
+
+			if !version.IsValid(pak.Version) {
+				log.Println("Version " + pak.Version + " of " + p.StorefrontName + " cannot be ordered, devices will offer any change as an update")
+
+				if *strict {
+					log.Fatal("pak.json for " + p.StorefrontName + " has a version that cannot be ordered")
+				}
+			}
+
+			pak.Screenshots = pinScreenshots(provider, ref, pak.Screenshots)
+			pak.ReleaseDate = release.PublishedAt
+
+			asset, ok := findReleaseAsset(release, pak.ReleaseFilename)
+			if ok {
+				pak.DownloadURL = asset.DownloadURL
+				pak.ReleaseSize = asset.Size
+				pak.ReleaseSHA256, err = assetSHA256(asset, p.LargePak)
+				if err != nil {
+					log.Println("Unable to determine checksum for "+p.StorefrontName, err)
+				}
+			} else {
+				// Nothing to download, so the pak cannot be offered until a release has the asset
+				log.Println("Release " + release.Tag + " of " + p.RepoURL + " has no asset named " + pak.ReleaseFilename + ", disabling it")
+				pak.DownloadURL = ""
+				pak.ReleaseSize = 0
+				pak.ReleaseSHA256 = ""
+				missingAsset = true
+			}
+
+			pak.License, err = provider.License()
+			if err != nil {
+				log.Println("Unable to fetch license for "+p.StorefrontName, err)
+			}
+
+			pak.Prerelease = fetchPrerelease(provider, release, pak.Version, p)
 		}
 
 		pak.StorefrontName = p.StorefrontName
 		pak.PreviousNames = p.PreviousNames
 		pak.RepoURL = p.RepoURL
+		pak.Forge = p.Forge
 		pak.Categories = p.Categories
 		pak.LargePak = p.LargePak
-		pak.Disabled = p.Disabled
+		pak.Disabled = p.Disabled || missingAsset
 
 		paks = append(paks, pak)
 	}
 
 	sf.Paks = paks
+	sf.GeneratedAt = time.Now().UTC().Truncate(time.Second)
+
+	previous, err := fetchPublishedStorefront(*published)
+	if err != nil {
+		log.Println("Unable to load the published storefront, skipping the diff", err)
+	} else {
+		diff := storefront.Compare(previous, sf)
+		writeDiff(diff)
+
+		// An unchanged storefront keeps its timestamp, so the published files stay byte for byte
+		// the same and devices' conditional requests and delta index keep hitting
+		if diff.IsEmpty() && sameContent(previous, sf) {
+			sf.GeneratedAt = previous.GeneratedAt
+		}
+
+		if problems := diff.Check(*maxRemoved); len(problems) > 0 {
+			for _, problem := range problems {
+				log.Println("Safety check failed:", problem)
+			}
+
+			if !*force {
+				log.Fatal("Refusing to publish storefront.json, rerun with -force to publish anyway")
+			}
+		}
+	}
 
 	jsonData, err := json.MarshalIndent(sf, "", "  ")
 	if err != nil {
 		log.Fatal("Unable to marshal storefront to JSON", err)
 	}
 
-	err = os.WriteFile("storefront.json", jsonData, 0644)
+	err = os.WriteFile(models.StorefrontJsonFilename, jsonData, 0644)
 	if err != nil {
 		log.Fatal("Unable to write storefront.json", err)
 	}
+
+	writeIndex(sf)
+
+	writeSchema(models.PakSchemaFilename, schema.Generate(models.PakSchemaURL, "pak.json", models.PakJson{}))
+	writeSchema(models.StorefrontSchemaFilename, schema.Generate(models.StorefrontSchemaURL, "storefront.json", models.Storefront{}))
 }
 
-func fetchPakJsonFromGitHubAPI(apiURL string) (models.Pak, error) {
-	var pak models.Pak
+func fetchPublishedStorefront(url string) (models.Storefront, error) {
+	var sf models.Storefront
 
-	req, err := http.NewRequest("GET", apiURL, nil)
+	resp, err := httpclient.Default().Get(url)
 	if err != nil {
-		return pak, fmt.Errorf("error creating HTTP request: %w", err)
+		return sf, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return sf, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&sf); err != nil {
+		return sf, fmt.Errorf("error decoding published storefront: %w", err)
 	}
 
-	req.Header.Add("Accept", "application/vnd.github.v3+json")
+	return sf, nil
+}
 
-	req.Header.Add("Authorization", "Bearer "+os.Getenv("GH_TOKEN"))
+// sameContent reports whether two storefronts only differ in when they were generated. The diff
+// leaves out changes like a changelog edit, this catches everything that would be published.
+func sameContent(previous models.Storefront, current models.Storefront) bool {
+	previous.GeneratedAt = current.GeneratedAt
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	a, errA := json.Marshal(previous)
+	b, errB := json.Marshal(current)
+
+	return errA == nil && errB == nil && bytes.Equal(a, b)
+}
+
+func writeDiff(diff storefront.Diff) {
+	jsonData, err := json.MarshalIndent(diff, "", "  ")
 	if err != nil {
-		return pak, fmt.Errorf("error making HTTP request: %w", err)
+		log.Fatal("Unable to marshal storefront diff", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return pak, fmt.Errorf("GitHub API error: %s - %s", resp.Status, string(body))
+	if err := os.WriteFile(models.StorefrontDiffJsonFilename, jsonData, 0644); err != nil {
+		log.Fatal("Unable to write "+models.StorefrontDiffJsonFilename, err)
 	}
 
-	var content GitHubContent
-	if err := json.NewDecoder(resp.Body).Decode(&content); err != nil {
-		return pak, fmt.Errorf("error decoding GitHub API response: %w", err)
+	if err := os.WriteFile(models.StorefrontDiffMarkdownFilename, []byte(diff.Markdown()), 0644); err != nil {
+		log.Fatal("Unable to write "+models.StorefrontDiffMarkdownFilename, err)
 	}
+}
 
-	if content.Encoding == "base64" {
-		contentBytes, err := base64.StdEncoding.DecodeString(
-			strings.ReplaceAll(content.Content, "\n", ""))
+// writeIndex publishes the delta format next to storefront.json so devices only
+// download the pak details that changed since their last launch.
+func writeIndex(sf models.Storefront) {
+	index, details, err := storefront.BuildIndex(sf)
+	if err != nil {
+		log.Fatal("Unable to build storefront index", err)
+	}
+
+	if err := os.RemoveAll(models.PakDetailsDir); err != nil {
+		log.Fatal("Unable to clear "+models.PakDetailsDir, err)
+	}
+
+	if err := os.MkdirAll(models.PakDetailsDir, 0755); err != nil {
+		log.Fatal("Unable to create "+models.PakDetailsDir, err)
+	}
+
+	for hash, data := range details {
+		err := os.WriteFile(filepath.Join(models.PakDetailsDir, hash+".json"), data, 0644)
 		if err != nil {
-			return pak, fmt.Errorf("error decoding base64 content: %w", err)
+			log.Fatal("Unable to write pak details", err)
+		}
+	}
+
+	jsonData, err := json.Marshal(index)
+	if err != nil {
+		log.Fatal("Unable to marshal storefront index", err)
+	}
+
+	if err := os.WriteFile(models.StorefrontIndexFilename, jsonData, 0644); err != nil {
+		log.Fatal("Unable to write "+models.StorefrontIndexFilename, err)
+	}
+}
+
+func writeSchema(filename string, s map[string]interface{}) {
+	jsonData, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		log.Fatal("Unable to marshal "+filename, err)
+	}
+
+	err = os.WriteFile(filename, jsonData, 0644)
+	if err != nil {
+		log.Fatal("Unable to write "+filename, err)
+	}
+}
+
+func findReleaseAsset(release forge.Release, filename string) (forge.Asset, bool) {
+	// GitHub replaces spaces in asset names with dots on upload
+	normalized := strings.ReplaceAll(filename, " ", ".")
+
+	for _, a := range release.Assets {
+		if a.Name == filename || a.Name == normalized {
+			return a, true
 		}
+	}
 
-		if err := json.Unmarshal(contentBytes, &pak); err != nil {
-			return pak, fmt.Errorf("error parsing pak.json: %w", err)
+	return forge.Asset{}, false
+}
+
+// assetSHA256 prefers the digest the forge records for the asset. When there is none,
+// the asset is downloaded and hashed unless it is flagged as a large pak.
+func assetSHA256(asset forge.Asset, largePak bool) (string, error) {
+	if asset.SHA256 != "" {
+		return asset.SHA256, nil
+	}
+
+	if largePak {
+		return "", nil
+	}
+
+	resp, err := httpclient.Default().Get(asset.DownloadURL)
+	if err != nil {
+		return "", fmt.Errorf("error downloading asset: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error downloading asset: %s", resp.Status)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, resp.Body); err != nil {
+		return "", fmt.Errorf("error hashing asset: %w", err)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// fetchPrerelease describes the newest prerelease when it was published after the stable release.
+// Problems are logged and leave the pak without a beta, they never fail the build.
+func fetchPrerelease(provider forge.Provider, stable forge.Release, stableVersion string, p models.Pak) *models.PakRelease {
+	release, ok, err := provider.LatestPrerelease()
+	if err != nil {
+		log.Println("Unable to fetch prereleases for "+p.StorefrontName, err)
+		return nil
+	}
+
+	if !ok || !release.PublishedAt.After(stable.PublishedAt) {
+		return nil
+	}
+
+	pak, _, err := fetchPakJson(provider, forge.TagRef(release.Tag))
+	if err != nil {
+		log.Println("Unable to fetch pak json for prerelease "+release.Tag+" of "+p.StorefrontName, err)
+		return nil
+	}
+
+	if version.Compare(pak.Version, stableVersion) <= 0 {
+		log.Println("Prerelease " + release.Tag + " of " + p.StorefrontName + " is not newer than the stable release, skipping it")
+		return nil
+	}
+
+	asset, ok := findReleaseAsset(release, pak.ReleaseFilename)
+	if !ok {
+		log.Println("Prerelease " + release.Tag + " of " + p.RepoURL + " has no asset named " + pak.ReleaseFilename)
+		return nil
+	}
+
+	sha, err := assetSHA256(asset, p.LargePak)
+	if err != nil {
+		log.Println("Unable to determine checksum for prerelease of "+p.StorefrontName, err)
+	}
+
+	return &models.PakRelease{
+		Version:         pak.Version,
+		ReleaseFilename: pak.ReleaseFilename,
+		Changelog:       pak.Changelog,
+		DownloadURL:     asset.DownloadURL,
+		ReleaseSize:     asset.Size,
+		ReleaseSHA256:   sha,
+		ReleaseDate:     release.PublishedAt,
+	}
+}
+
+// fetchPakJson returns the parsed pak.json at ref along with every schema problem found in it.
+// Only the fields authors own are read, the builder fills in the rest.
+func fetchPakJson(provider forge.Provider, ref string) (models.Pak, []error, error) {
+	var pj models.PakJson
+
+	contentBytes, err := provider.File(ref, models.PakJsonStub)
+	if err != nil {
+		return models.Pak{}, nil, err
+	}
+
+	problems := schema.Check(contentBytes, pj)
+
+	if err := json.Unmarshal(contentBytes, &pj); err != nil {
+		return models.Pak{}, problems, fmt.Errorf("error parsing pak.json: %w", err)
+	}
+
+	return pj.Pak(), problems, nil
+}
+
+// pinScreenshots turns repo relative screenshot paths into raw URLs at the release tag,
+// so the images always match the published version.
+func pinScreenshots(provider forge.Provider, ref string, screenshots []string) []string {
+	pinned := make([]string, 0, len(screenshots))
+
+	for _, s := range screenshots {
+		if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+			pinned = append(pinned, s)
+			continue
 		}
-	} else {
-		return pak, fmt.Errorf("unexpected content encoding: %s", content.Encoding)
+
+		pinned = append(pinned, provider.RawFileURL(ref, s))
 	}
 
-	return pak, nil
+	return pinned
 }
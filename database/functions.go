@@ -11,7 +11,6 @@ import (
 	"path/filepath"
 
 	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
-	pakstore "github.com/UncleJunVIP/nextui-pak-store"
 	"github.com/UncleJunVIP/nextui-pak-store/models"
 	"github.com/UncleJunVIP/nextui-pak-store/utils"
 	_ "modernc.org/sqlite"
@@ -25,11 +24,7 @@ func Init() {
 	ctx := context.Background()
 
 	var err error
-	dbPath := filepath.Join(models.PakStoreConfigRoot, "pak-store.db")
-
-	if os.Getenv("ENVIRONMENT") == "DEV" {
-		dbPath = "pak-store.db"
-	}
+	dbPath := databasePath()
 
 	dbDir := filepath.Dir(dbPath)
 	if dbDir != "." && dbDir != "" {
@@ -47,14 +42,15 @@ func Init() {
 	}
 
 	schemaExists, err := tableExists(dbc, "installed_paks")
-	if !schemaExists {
-		if _, err := dbc.ExecContext(ctx, pakstore.DDL); err != nil {
-			logger.Error("Unable to Init schema", "error", err)
-			os.Exit(1)
-		}
+	if err != nil {
+		logger.Error("Unable to read schema", "error", err)
+		os.Exit(1)
 	}
 
-	columnMigration("installed_paks", "repo_url", "TEXT")
+	if err := migrate(dbPath); err != nil {
+		logger.Error("Unable to migrate database", "error", err)
+		os.Exit(1)
+	}
 
 	queries = New(dbc)
 
@@ -85,23 +81,43 @@ func DBQ() *Queries {
 	return queries
 }
 
+// WithTx runs fn inside a transaction that is committed only when fn succeeds.
+// Work outside the database belongs at the end of fn so a failure there rolls the bookkeeping back.
+func WithTx(ctx context.Context, fn func(q *Queries) error) error {
+	tx, err := dbc.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to begin transaction: %w", err)
+	}
+
+	if err := fn(queries.WithTx(tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
 func CloseDB() {
 	_ = dbc.Close()
 }
 
-func tableExists(db *sql.DB, tableName string) (bool, error) {
+func tableExists(db DBTX, tableName string) (bool, error) {
 	query := `SELECT name FROM sqlite_master WHERE type='table' AND name=?`
 	var name string
-	err := db.QueryRow(query, tableName).Scan(&name)
+	err := db.QueryRowContext(context.Background(), query, tableName).Scan(&name)
 	if errors.Is(err, sql.ErrNoRows) {
 		return false, nil
 	}
 	return err == nil, err
 }
 
-func columnExists(db *sql.DB, tableName, columnName string) (bool, error) {
+func columnExists(db DBTX, tableName, columnName string) (bool, error) {
 	query := fmt.Sprintf("PRAGMA table_info(%s)", tableName)
-	rows, err := db.Query(query)
+	rows, err := db.QueryContext(context.Background(), query)
 	if err != nil {
 		return false, err
 	}
@@ -126,22 +142,23 @@ func columnExists(db *sql.DB, tableName, columnName string) (bool, error) {
 	return false, rows.Err()
 }
 
-func columnMigration(tableName, columnName, columnDefinition string) {
+// columnMigration adds a column unless it is already there. Only databases from before versioned
+// migrations need it, new columns belong in a migration.
+func columnMigration(db DBTX, tableName, columnName, columnDefinition string) error {
 	logger := common.GetLoggerInstance()
-	ctx := context.Background()
 
-	ce, err := columnExists(dbc, tableName, columnName)
+	ce, err := columnExists(db, tableName, columnName)
 	if err != nil {
-		logger.Error("Unable to check column existence", "error", err)
-		os.Exit(1)
+		return fmt.Errorf("unable to check column existence: %w", err)
 	}
 
 	if !ce {
 		migrationSQL := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", tableName, columnName, columnDefinition)
-		if _, err := dbc.ExecContext(ctx, migrationSQL); err != nil {
-			logger.Error("Unable to run column migration", "error", err)
-			os.Exit(1)
+		if _, err := db.ExecContext(context.Background(), migrationSQL); err != nil {
+			return fmt.Errorf("unable to add column %s: %w", columnName, err)
 		}
 		logger.Info("Successfully added column", "column", columnName)
 	}
+
+	return nil
 }
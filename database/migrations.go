@@ -0,0 +1,220 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	pakstore "github.com/UncleJunVIP/nextui-pak-store"
+)
+
+const migrationsDir = "sql/migrations"
+
+// legacySchemaVersion is the migration that matches the schema databases reached before
+// migrations were versioned. Those databases are brought up to it column by column.
+const legacySchemaVersion = 5
+
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// loadMigrations reads the embedded migrations, named like 0001_description.sql, in version order.
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(pakstore.Migrations, migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []migration
+	for _, e := range entries {
+		prefix, _, ok := strings.Cut(e.Name(), "_")
+		if !ok || path.Ext(e.Name()) != ".sql" {
+			continue
+		}
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s is not numbered: %w", e.Name(), err)
+		}
+
+		data, err := fs.ReadFile(pakstore.Migrations, path.Join(migrationsDir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, migration{version: version, name: e.Name(), sql: string(data)})
+	}
+
+	slices.SortFunc(migrations, func(a, b migration) int {
+		return a.version - b.version
+	})
+
+	for i, m := range migrations {
+		if m.version != i+1 {
+			return nil, fmt.Errorf("migration %s is out of sequence, expected version %d", m.name, i+1)
+		}
+	}
+
+	return migrations, nil
+}
+
+// migrate brings the database at dbPath up to the newest migration. The file is backed up before
+// the first pending migration runs and every migration commits together with its version number.
+func migrate(dbPath string) error {
+	logger := common.GetLoggerInstance()
+	ctx := context.Background()
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return fmt.Errorf("unable to load migrations: %w", err)
+	}
+
+	current, err := userVersion()
+	if err != nil {
+		return err
+	}
+
+	existing, err := tableExists(dbc, "installed_paks")
+	if err != nil {
+		return err
+	}
+
+	if current > len(migrations) {
+		return fmt.Errorf("database version %d is newer than this Pak Store supports (%d)", current, len(migrations))
+	}
+
+	if existing && current < len(migrations) {
+		if err := backup(dbPath, current); err != nil {
+			return err
+		}
+	}
+
+	if existing && current == 0 {
+		if err := adoptLegacySchema(migrations); err != nil {
+			return fmt.Errorf("unable to adopt the existing schema: %w", err)
+		}
+
+		current = legacySchemaVersion
+	}
+
+	for _, m := range migrations[current:] {
+		tx, err := dbc.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, m.sql); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %s failed: %w", m.name, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", m.version)); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("unable to record migration %s: %w", m.name, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("unable to commit migration %s: %w", m.name, err)
+		}
+
+		logger.Info("Applied database migration", "migration", m.name)
+	}
+
+	return nil
+}
+
+// adoptLegacySchema upgrades a database from before versioned migrations, which may have any of
+// the tables and columns that were added on the fly back then, to legacySchemaVersion.
+func adoptLegacySchema(migrations []migration) error {
+	ctx := context.Background()
+
+	tx, err := dbc.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	exists, err := tableExists(tx, "storefront_mirrors")
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		if _, err := tx.ExecContext(ctx, migrations[1].sql); err != nil {
+			return err
+		}
+	}
+
+	columns := []struct{ name, definition string }{
+		{"repo_url", "TEXT"},
+		{"auto_update", "INT NOT NULL DEFAULT 0"},
+		{"beta", "INT NOT NULL DEFAULT 0"},
+		{"installed_at", "TEXT"},
+		{"updated_at", "TEXT"},
+		{"source_storefront", "TEXT"},
+		{"download_url", "TEXT"},
+		{"archive_sha256", "TEXT"},
+		{"install_paths", "TEXT"},
+		{"installed_by", "TEXT"},
+	}
+
+	for _, c := range columns {
+		if err := columnMigration(tx, "installed_paks", c.name, c.definition); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", legacySchemaVersion)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func userVersion() (int, error) {
+	var version int
+	if err := dbc.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("unable to read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// backup copies the database file before it is upgraded from version, keeping one copy per version.
+func backup(dbPath string, version int) error {
+	logger := common.GetLoggerInstance()
+
+	src, err := os.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("unable to back up database: %w", err)
+	}
+	defer src.Close()
+
+	backupPath := fmt.Sprintf("%s.v%d.bak", dbPath, version)
+
+	dst, err := os.Create(backupPath)
+	if err != nil {
+		return fmt.Errorf("unable to back up database: %w", err)
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return fmt.Errorf("unable to back up database: %w", err)
+	}
+
+	if err := dst.Close(); err != nil {
+		return fmt.Errorf("unable to back up database: %w", err)
+	}
+
+	logger.Info("Backed up database before migrating", "backup", backupPath)
+
+	return nil
+}
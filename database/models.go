@@ -8,11 +8,58 @@ import (
 	"database/sql"
 )
 
+type Favorite struct {
+	RepoUrl   string
+	Name      string
+	StarredAt string
+}
+
+type History struct {
+	ID          int64
+	RepoUrl     sql.NullString
+	PakName     string
+	Action      string
+	FromVersion sql.NullString
+	ToVersion   sql.NullString
+	Succeeded   int64
+	Error       sql.NullString
+	StartedAt   string
+	DurationMs  int64
+}
+
 type InstalledPak struct {
-	Name         string
-	DisplayName  string
-	RepoUrl      sql.NullString
-	Type         string
-	Version      string
-	CanUninstall int64
+	Name             string
+	DisplayName      string
+	RepoUrl          sql.NullString
+	Type             string
+	Version          string
+	CanUninstall     int64
+	AutoUpdate       int64
+	Beta             int64
+	InstalledAt      sql.NullString
+	UpdatedAt        sql.NullString
+	SourceStorefront sql.NullString
+	DownloadUrl      sql.NullString
+	ArchiveSha256    sql.NullString
+	InstallPaths     sql.NullString
+	InstalledBy      sql.NullString
+}
+
+type Setting struct {
+	Key   string
+	Value string
+}
+
+type StorefrontMirror struct {
+	Url                 string
+	Priority            int64
+	Builtin             int64
+	Successes           int64
+	Failures            int64
+	ConsecutiveFailures int64
+	AvgLatencyMs        int64
+	LastSuccess         sql.NullString
+	LastFailure         sql.NullString
+	LastError           sql.NullString
+	LastGeneratedAt     sql.NullString
 }
@@ -0,0 +1,13 @@
+//go:build !dev
+
+package database
+
+import (
+	"path/filepath"
+
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+)
+
+func databasePath() string {
+	return filepath.Join(models.PakStoreConfigRoot, "pak-store.db")
+}
@@ -0,0 +1,8 @@
+//go:build dev
+
+package database
+
+// Dev builds keep the database in the working directory.
+func databasePath() string {
+	return "pak-store.db"
+}
@@ -10,18 +10,82 @@ import (
 	"database/sql"
 )
 
+const addFavorite = `-- name: AddFavorite :exec
+INSERT INTO favorites (repo_url, name, starred_at)
+VALUES (?, ?, ?)
+ON CONFLICT (repo_url) DO NOTHING
+`
+
+type AddFavoriteParams struct {
+	RepoUrl   string
+	Name      string
+	StarredAt string
+}
+
+func (q *Queries) AddFavorite(ctx context.Context, arg AddFavoriteParams) error {
+	_, err := q.db.ExecContext(ctx, addFavorite, arg.RepoUrl, arg.Name, arg.StarredAt)
+	return err
+}
+
+const deleteMirror = `-- name: DeleteMirror :exec
+DELETE
+FROM storefront_mirrors
+WHERE url = ?
+`
+
+func (q *Queries) DeleteMirror(ctx context.Context, url string) error {
+	_, err := q.db.ExecContext(ctx, deleteMirror, url)
+	return err
+}
+
+const getInstalledPak = `-- name: GetInstalledPak :one
+SELECT name, display_name, repo_url, type, version, can_uninstall, auto_update, beta, installed_at, updated_at, source_storefront, download_url, archive_sha256, install_paths, installed_by
+FROM installed_paks
+WHERE repo_url = ?
+`
+
+func (q *Queries) GetInstalledPak(ctx context.Context, repoUrl sql.NullString) (InstalledPak, error) {
+	row := q.db.QueryRowContext(ctx, getInstalledPak, repoUrl)
+	var i InstalledPak
+	err := row.Scan(
+		&i.Name,
+		&i.DisplayName,
+		&i.RepoUrl,
+		&i.Type,
+		&i.Version,
+		&i.CanUninstall,
+		&i.AutoUpdate,
+		&i.Beta,
+		&i.InstalledAt,
+		&i.UpdatedAt,
+		&i.SourceStorefront,
+		&i.DownloadUrl,
+		&i.ArchiveSha256,
+		&i.InstallPaths,
+		&i.InstalledBy,
+	)
+	return i, err
+}
+
 const install = `-- name: Install :exec
-INSERT INTO installed_paks (display_name, name, repo_url, version, type, can_uninstall)
-VALUES (?, ?, ?, ?, ?, ?)
+INSERT INTO installed_paks (display_name, name, repo_url, version, type, can_uninstall, installed_at,
+                            source_storefront, download_url, archive_sha256, install_paths, installed_by)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type InstallParams struct {
-	DisplayName  string
-	Name         string
-	RepoUrl      sql.NullString
-	Version      string
-	Type         string
-	CanUninstall int64
+	DisplayName      string
+	Name             string
+	RepoUrl          sql.NullString
+	Version          string
+	Type             string
+	CanUninstall     int64
+	InstalledAt      sql.NullString
+	SourceStorefront sql.NullString
+	DownloadUrl      sql.NullString
+	ArchiveSha256    sql.NullString
+	InstallPaths     sql.NullString
+	InstalledBy      sql.NullString
 }
 
 func (q *Queries) Install(ctx context.Context, arg InstallParams) error {
@@ -32,12 +96,134 @@ func (q *Queries) Install(ctx context.Context, arg InstallParams) error {
 		arg.Version,
 		arg.Type,
 		arg.CanUninstall,
+		arg.InstalledAt,
+		arg.SourceStorefront,
+		arg.DownloadUrl,
+		arg.ArchiveSha256,
+		arg.InstallPaths,
+		arg.InstalledBy,
 	)
 	return err
 }
 
+const listAutoUpdatePaks = `-- name: ListAutoUpdatePaks :many
+SELECT name, display_name, repo_url, type, version, can_uninstall, auto_update, beta, installed_at, updated_at, source_storefront, download_url, archive_sha256, install_paths, installed_by
+FROM installed_paks
+WHERE auto_update = 1
+ORDER BY name
+`
+
+func (q *Queries) ListAutoUpdatePaks(ctx context.Context) ([]InstalledPak, error) {
+	rows, err := q.db.QueryContext(ctx, listAutoUpdatePaks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []InstalledPak
+	for rows.Next() {
+		var i InstalledPak
+		if err := rows.Scan(
+			&i.Name,
+			&i.DisplayName,
+			&i.RepoUrl,
+			&i.Type,
+			&i.Version,
+			&i.CanUninstall,
+			&i.AutoUpdate,
+			&i.Beta,
+			&i.InstalledAt,
+			&i.UpdatedAt,
+			&i.SourceStorefront,
+			&i.DownloadUrl,
+			&i.ArchiveSha256,
+			&i.InstallPaths,
+			&i.InstalledBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFavorites = `-- name: ListFavorites :many
+SELECT repo_url, name, starred_at
+FROM favorites
+ORDER BY name
+`
+
+func (q *Queries) ListFavorites(ctx context.Context) ([]Favorite, error) {
+	rows, err := q.db.QueryContext(ctx, listFavorites)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Favorite
+	for rows.Next() {
+		var i Favorite
+		if err := rows.Scan(&i.RepoUrl, &i.Name, &i.StarredAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHistory = `-- name: ListHistory :many
+SELECT id, repo_url, pak_name, action, from_version, to_version, succeeded, error, started_at, duration_ms
+FROM history
+ORDER BY id DESC
+LIMIT ?
+`
+
+func (q *Queries) ListHistory(ctx context.Context, limit int64) ([]History, error) {
+	rows, err := q.db.QueryContext(ctx, listHistory, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []History
+	for rows.Next() {
+		var i History
+		if err := rows.Scan(
+			&i.ID,
+			&i.RepoUrl,
+			&i.PakName,
+			&i.Action,
+			&i.FromVersion,
+			&i.ToVersion,
+			&i.Succeeded,
+			&i.Error,
+			&i.StartedAt,
+			&i.DurationMs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listInstalledPaks = `-- name: ListInstalledPaks :many
-SELECT name, display_name, repo_url, type, version, can_uninstall
+SELECT name, display_name, repo_url, type, version, can_uninstall, auto_update, beta, installed_at, updated_at, source_storefront, download_url, archive_sha256, install_paths, installed_by
 FROM installed_paks
 WHERE can_uninstall = 1
 ORDER BY name
@@ -59,6 +245,15 @@ func (q *Queries) ListInstalledPaks(ctx context.Context) ([]InstalledPak, error)
 			&i.Type,
 			&i.Version,
 			&i.CanUninstall,
+			&i.AutoUpdate,
+			&i.Beta,
+			&i.InstalledAt,
+			&i.UpdatedAt,
+			&i.SourceStorefront,
+			&i.DownloadUrl,
+			&i.ArchiveSha256,
+			&i.InstallPaths,
+			&i.InstalledBy,
 		); err != nil {
 			return nil, err
 		}
@@ -74,7 +269,7 @@ func (q *Queries) ListInstalledPaks(ctx context.Context) ([]InstalledPak, error)
 }
 
 const listInstalledPaksWithoutRepo = `-- name: ListInstalledPaksWithoutRepo :many
-SELECT name, display_name, repo_url, type, version, can_uninstall
+SELECT name, display_name, repo_url, type, version, can_uninstall, auto_update, beta, installed_at, updated_at, source_storefront, download_url, archive_sha256, install_paths, installed_by
 FROM installed_paks
 WHERE repo_url IS NULL
 `
@@ -95,6 +290,15 @@ func (q *Queries) ListInstalledPaksWithoutRepo(ctx context.Context) ([]Installed
 			&i.Type,
 			&i.Version,
 			&i.CanUninstall,
+			&i.AutoUpdate,
+			&i.Beta,
+			&i.InstalledAt,
+			&i.UpdatedAt,
+			&i.SourceStorefront,
+			&i.DownloadUrl,
+			&i.ArchiveSha256,
+			&i.InstallPaths,
+			&i.InstalledBy,
 		); err != nil {
 			return nil, err
 		}
@@ -109,6 +313,261 @@ func (q *Queries) ListInstalledPaksWithoutRepo(ctx context.Context) ([]Installed
 	return items, nil
 }
 
+const listMirrors = `-- name: ListMirrors :many
+SELECT url, priority, builtin, successes, failures, consecutive_failures, avg_latency_ms, last_success, last_failure, last_error, last_generated_at
+FROM storefront_mirrors
+ORDER BY priority
+`
+
+func (q *Queries) ListMirrors(ctx context.Context) ([]StorefrontMirror, error) {
+	rows, err := q.db.QueryContext(ctx, listMirrors)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StorefrontMirror
+	for rows.Next() {
+		var i StorefrontMirror
+		if err := rows.Scan(
+			&i.Url,
+			&i.Priority,
+			&i.Builtin,
+			&i.Successes,
+			&i.Failures,
+			&i.ConsecutiveFailures,
+			&i.AvgLatencyMs,
+			&i.LastSuccess,
+			&i.LastFailure,
+			&i.LastError,
+			&i.LastGeneratedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSettings = `-- name: ListSettings :many
+SELECT key, value
+FROM settings
+`
+
+func (q *Queries) ListSettings(ctx context.Context) ([]Setting, error) {
+	rows, err := q.db.QueryContext(ctx, listSettings)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Setting
+	for rows.Next() {
+		var i Setting
+		if err := rows.Scan(&i.Key, &i.Value); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordHistory = `-- name: RecordHistory :exec
+INSERT INTO history (repo_url, pak_name, action, from_version, to_version, succeeded, error, started_at, duration_ms)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type RecordHistoryParams struct {
+	RepoUrl     sql.NullString
+	PakName     string
+	Action      string
+	FromVersion sql.NullString
+	ToVersion   sql.NullString
+	Succeeded   int64
+	Error       sql.NullString
+	StartedAt   string
+	DurationMs  int64
+}
+
+func (q *Queries) RecordHistory(ctx context.Context, arg RecordHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, recordHistory,
+		arg.RepoUrl,
+		arg.PakName,
+		arg.Action,
+		arg.FromVersion,
+		arg.ToVersion,
+		arg.Succeeded,
+		arg.Error,
+		arg.StartedAt,
+		arg.DurationMs,
+	)
+	return err
+}
+
+const recordMirrorFailure = `-- name: RecordMirrorFailure :exec
+UPDATE storefront_mirrors
+SET failures             = failures + 1,
+    consecutive_failures = consecutive_failures + 1,
+    last_failure         = ?1,
+    last_error           = ?2
+WHERE url = ?3
+`
+
+type RecordMirrorFailureParams struct {
+	LastFailure sql.NullString
+	LastError   sql.NullString
+	Url         string
+}
+
+func (q *Queries) RecordMirrorFailure(ctx context.Context, arg RecordMirrorFailureParams) error {
+	_, err := q.db.ExecContext(ctx, recordMirrorFailure, arg.LastFailure, arg.LastError, arg.Url)
+	return err
+}
+
+const recordMirrorSuccess = `-- name: RecordMirrorSuccess :exec
+UPDATE storefront_mirrors
+SET successes            = successes + 1,
+    consecutive_failures = 0,
+    avg_latency_ms       = ?1,
+    last_success         = ?2,
+    last_generated_at    = ?3
+WHERE url = ?4
+`
+
+type RecordMirrorSuccessParams struct {
+	AvgLatencyMs    int64
+	LastSuccess     sql.NullString
+	LastGeneratedAt sql.NullString
+	Url             string
+}
+
+func (q *Queries) RecordMirrorSuccess(ctx context.Context, arg RecordMirrorSuccessParams) error {
+	_, err := q.db.ExecContext(ctx, recordMirrorSuccess,
+		arg.AvgLatencyMs,
+		arg.LastSuccess,
+		arg.LastGeneratedAt,
+		arg.Url,
+	)
+	return err
+}
+
+const recordUpdate = `-- name: RecordUpdate :exec
+UPDATE installed_paks
+SET version           = ?,
+    updated_at        = ?,
+    source_storefront = ?,
+    download_url      = ?,
+    archive_sha256    = ?,
+    install_paths     = ?,
+    installed_by      = ?
+WHERE repo_url = ?
+`
+
+type RecordUpdateParams struct {
+	Version          string
+	UpdatedAt        sql.NullString
+	SourceStorefront sql.NullString
+	DownloadUrl      sql.NullString
+	ArchiveSha256    sql.NullString
+	InstallPaths     sql.NullString
+	InstalledBy      sql.NullString
+	RepoUrl          sql.NullString
+}
+
+func (q *Queries) RecordUpdate(ctx context.Context, arg RecordUpdateParams) error {
+	_, err := q.db.ExecContext(ctx, recordUpdate,
+		arg.Version,
+		arg.UpdatedAt,
+		arg.SourceStorefront,
+		arg.DownloadUrl,
+		arg.ArchiveSha256,
+		arg.InstallPaths,
+		arg.InstalledBy,
+		arg.RepoUrl,
+	)
+	return err
+}
+
+const removeFavorite = `-- name: RemoveFavorite :exec
+DELETE
+FROM favorites
+WHERE repo_url = ?
+`
+
+func (q *Queries) RemoveFavorite(ctx context.Context, repoUrl string) error {
+	_, err := q.db.ExecContext(ctx, removeFavorite, repoUrl)
+	return err
+}
+
+const setAutoUpdate = `-- name: SetAutoUpdate :exec
+UPDATE installed_paks
+SET auto_update = ?
+WHERE repo_url = ?
+`
+
+type SetAutoUpdateParams struct {
+	AutoUpdate int64
+	RepoUrl    sql.NullString
+}
+
+func (q *Queries) SetAutoUpdate(ctx context.Context, arg SetAutoUpdateParams) error {
+	_, err := q.db.ExecContext(ctx, setAutoUpdate, arg.AutoUpdate, arg.RepoUrl)
+	return err
+}
+
+const setBeta = `-- name: SetBeta :exec
+UPDATE installed_paks
+SET beta = ?
+WHERE repo_url = ?
+`
+
+type SetBetaParams struct {
+	Beta    int64
+	RepoUrl sql.NullString
+}
+
+func (q *Queries) SetBeta(ctx context.Context, arg SetBetaParams) error {
+	_, err := q.db.ExecContext(ctx, setBeta, arg.Beta, arg.RepoUrl)
+	return err
+}
+
+const setBetaForAll = `-- name: SetBetaForAll :exec
+UPDATE installed_paks
+SET beta = ?
+WHERE can_uninstall = 1
+`
+
+func (q *Queries) SetBetaForAll(ctx context.Context, beta int64) error {
+	_, err := q.db.ExecContext(ctx, setBetaForAll, beta)
+	return err
+}
+
+const setSetting = `-- name: SetSetting :exec
+INSERT INTO settings (key, value)
+VALUES (?, ?)
+ON CONFLICT (key) DO UPDATE SET value = excluded.value
+`
+
+type SetSettingParams struct {
+	Key   string
+	Value string
+}
+
+func (q *Queries) SetSetting(ctx context.Context, arg SetSettingParams) error {
+	_, err := q.db.ExecContext(ctx, setSetting, arg.Key, arg.Value)
+	return err
+}
+
 const uninstall = `-- name: Uninstall :exec
 DELETE
 FROM installed_paks
@@ -160,3 +619,21 @@ func (q *Queries) UpdateVersion(ctx context.Context, arg UpdateVersionParams) er
 	_, err := q.db.ExecContext(ctx, updateVersion, arg.Version, arg.RepoUrl)
 	return err
 }
+
+const upsertMirror = `-- name: UpsertMirror :exec
+INSERT INTO storefront_mirrors (url, priority, builtin)
+VALUES (?, ?, ?)
+ON CONFLICT (url) DO UPDATE SET priority = excluded.priority,
+                                builtin  = excluded.builtin
+`
+
+type UpsertMirrorParams struct {
+	Url      string
+	Priority int64
+	Builtin  int64
+}
+
+func (q *Queries) UpsertMirror(ctx context.Context, arg UpsertMirrorParams) error {
+	_, err := q.db.ExecContext(ctx, upsertMirror, arg.Url, arg.Priority, arg.Builtin)
+	return err
+}
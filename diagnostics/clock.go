@@ -0,0 +1,73 @@
+package diagnostics
+
+import (
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// maxClockSkew is how far off the clock may be before it is reported. Certificates are
+// valid for months, so only a badly wrong clock breaks TLS, but anything past this is worth fixing.
+const maxClockSkew = 10 * time.Minute
+
+// ClockReading is the time a server reported in its Date header and when we saw it.
+type ClockReading struct {
+	ServerTime time.Time
+	ObservedAt time.Time
+}
+
+func readClock(resp *http.Response) ClockReading {
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return ClockReading{}
+	}
+
+	return ClockReading{ServerTime: date, ObservedAt: time.Now()}
+}
+
+func (c ClockReading) Valid() bool {
+	return !c.ServerTime.IsZero()
+}
+
+// Skew is how far the device clock is ahead of the server. Negative means behind.
+func (c ClockReading) Skew() time.Duration {
+	return c.ObservedAt.Sub(c.ServerTime)
+}
+
+func (c ClockReading) IsSkewed() bool {
+	skew := c.Skew()
+	return c.Valid() && (skew > maxClockSkew || skew < -maxClockSkew)
+}
+
+// Now estimates the current time from the server reading.
+func (c ClockReading) Now() time.Time {
+	return c.ServerTime.Add(time.Since(c.ObservedAt))
+}
+
+// isCertificateTimeError reports whether a certificate was rejected for being expired
+// or not yet valid, which is what a wrong device clock looks like.
+func isCertificateTimeError(err error) bool {
+	var invalid x509.CertificateInvalidError
+	return errors.As(err, &invalid) && invalid.Reason == x509.Expired
+}
+
+func CanSetClock() bool {
+	return os.Getenv("ENVIRONMENT") != "DEV" && os.Geteuid() == 0
+}
+
+// SetClock sets the system time and, where a hardware clock is available, stores it there
+// so the correction survives a reboot.
+func SetClock(t time.Time) error {
+	tv := syscall.NsecToTimeval(t.UnixNano())
+	if err := syscall.Settimeofday(&tv); err != nil {
+		return err
+	}
+
+	_ = exec.Command("hwclock", "-w", "-u").Run()
+
+	return nil
+}
@@ -0,0 +1,316 @@
+package diagnostics
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/UncleJunVIP/nextui-pak-store/httpclient"
+	"github.com/UncleJunVIP/nextui-pak-store/settings"
+	"qlova.tech/sum"
+)
+
+const captivePortalCheckURL = "http://connectivitycheck.gstatic.com/generate_204"
+
+type Kind struct {
+	Unknown,
+	NoNetwork,
+	DNS,
+	Unreachable,
+	CaptivePortal,
+	TLS,
+	HTTPStatus,
+	MalformedJSON,
+	ClockSkew sum.Int[Kind]
+}
+
+var Kinds = sum.Int[Kind]{}.Sum()
+
+type Probe struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+}
+
+// Report explains why the storefront could not be loaded.
+type Report struct {
+	Kind       sum.Int[Kind]
+	StatusCode int
+	Cause      error
+	Clock      ClockReading
+	Probes     []Probe
+}
+
+// Run probes the network from the interface up to the storefront host and attributes
+// cause, the error returned while loading the storefront, to the first layer that fails.
+func Run(cause error) Report {
+	host := storefrontHost()
+
+	report := Report{Cause: cause}
+	kind := Kinds.Unknown
+
+	fail := func(k sum.Int[Kind]) {
+		if kind == Kinds.Unknown {
+			kind = k
+		}
+	}
+
+	network := probeNetwork()
+	report.Probes = append(report.Probes, network)
+	if !network.OK {
+		fail(Kinds.NoNetwork)
+	}
+
+	dns := probeDNS(host)
+	report.Probes = append(report.Probes, dns)
+	if !dns.OK && network.OK {
+		fail(Kinds.DNS)
+	}
+
+	portal, captive, clock := probeCaptivePortal()
+	report.Probes = append(report.Probes, portal)
+	report.Clock = clock
+	if captive {
+		fail(Kinds.CaptivePortal)
+	} else if !portal.OK && dns.OK {
+		fail(Kinds.Unreachable)
+	}
+
+	if clock.Valid() {
+		report.Probes = append(report.Probes, probeClock(clock))
+	}
+
+	secure, tlsErr := probeTLS(host)
+	report.Probes = append(report.Probes, secure)
+	if tlsErr != nil && (isCertificateTimeError(tlsErr) || clock.IsSkewed()) {
+		fail(Kinds.ClockSkew)
+	} else if tlsErr != nil {
+		fail(Kinds.TLS)
+	}
+
+	if cause != nil {
+		report.Probes = append(report.Probes, Probe{Name: "Storefront", OK: false, Detail: cause.Error()})
+
+		if Classify(cause) == Kinds.TLS && (isCertificateTimeError(cause) || clock.IsSkewed()) {
+			fail(Kinds.ClockSkew)
+		}
+		fail(Classify(cause))
+	}
+
+	var statusErr *httpclient.StatusError
+	if errors.As(cause, &statusErr) {
+		report.StatusCode = statusErr.StatusCode
+	}
+
+	report.Kind = kind
+	return report
+}
+
+// Classify attributes an error to the layer it came from.
+func Classify(err error) sum.Int[Kind] {
+	if err == nil {
+		return Kinds.Unknown
+	}
+
+	var dnsErr *net.DNSError
+	var statusErr *httpclient.StatusError
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case isTLSError(err):
+		return Kinds.TLS
+	case errors.As(err, &dnsErr):
+		return Kinds.DNS
+	case errors.As(err, &statusErr):
+		return Kinds.HTTPStatus
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr):
+		return Kinds.MalformedJSON
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return Kinds.Unreachable
+	}
+
+	return Kinds.Unknown
+}
+
+func isTLSError(err error) bool {
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	var verification *tls.CertificateVerificationError
+	var recordHeader tls.RecordHeaderError
+
+	return errors.As(err, &unknownAuthority) ||
+		errors.As(err, &invalid) ||
+		errors.As(err, &hostname) ||
+		errors.As(err, &verification) ||
+		errors.As(err, &recordHeader)
+}
+
+// Message is shown to the user in place of the generic Wi-Fi hint.
+func (r Report) Message() string {
+	switch r.Kind {
+	case Kinds.NoNetwork:
+		return "Your device is not connected to a network.\nConnect to Wi-Fi and try again."
+	case Kinds.DNS:
+		return "Unable to look up the Storefront address.\nThe network's DNS may be down or blocking it."
+	case Kinds.Unreachable:
+		return "Connected to Wi-Fi but unable to reach the internet.\nCheck your router or try another network."
+	case Kinds.CaptivePortal:
+		return "This Wi-Fi network requires signing in.\nSign in from another device, then try again."
+	case Kinds.TLS:
+		return "Unable to establish a secure connection.\nThe network may be intercepting HTTPS traffic."
+	case Kinds.ClockSkew:
+		if r.Clock.Valid() {
+			return fmt.Sprintf("Your device clock is wrong, it says %s.\nSecure connections fail until it is corrected.",
+				r.Clock.ObservedAt.Format("Jan 2, 2006 15:04"))
+		}
+		return "Your device clock appears to be wrong.\nSecure connections fail until it is corrected."
+	case Kinds.HTTPStatus:
+		return fmt.Sprintf("The Storefront server returned an error (%d).\nPlease try again later.", r.StatusCode)
+	case Kinds.MalformedJSON:
+		return "The Storefront download was incomplete or invalid.\nPlease try again later."
+	}
+
+	return "Make sure you are connected to Wi-Fi."
+}
+
+// storefrontHost is the host of the storefront Pak Store tries first.
+func storefrontHost() string {
+	s := settings.Get()
+
+	source := s.StorefrontOverride
+	if source == "" {
+		source = s.StorefrontURLs[0]
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+func probeNetwork() Probe {
+	probe := Probe{Name: "Network"}
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		probe.Detail = err.Error()
+		return probe
+	}
+
+	for _, iface := range interfaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if ok && ipNet.IP.To4() != nil && !ipNet.IP.IsLinkLocalUnicast() {
+				probe.OK = true
+				probe.Detail = fmt.Sprintf("%s %s", iface.Name, ipNet.IP)
+				return probe
+			}
+		}
+	}
+
+	probe.Detail = "No interface with an IP address"
+	return probe
+}
+
+func probeDNS(host string) Probe {
+	probe := Probe{Name: "DNS"}
+
+	addrs, err := net.LookupHost(host)
+	if err != nil {
+		probe.Detail = err.Error()
+		return probe
+	}
+
+	probe.OK = true
+	probe.Detail = fmt.Sprintf("%s resolved to %s", host, strings.Join(addrs, ", "))
+	return probe
+}
+
+// probeCaptivePortal requests a page that always answers 204. Anything else means a
+// portal intercepted the request. The request is plain HTTP, so its Date header can be read
+// even when the device clock is too wrong for certificates to validate.
+func probeCaptivePortal() (probe Probe, captive bool, clock ClockReading) {
+	probe = Probe{Name: "Internet"}
+
+	client := *httpclient.Default()
+	client.Timeout = 10 * time.Second
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := client.Get(captivePortalCheckURL)
+	if err != nil {
+		probe.Detail = err.Error()
+		return probe, false, clock
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		probe.Detail = fmt.Sprintf("Sign-in page detected (status %d)", resp.StatusCode)
+		return probe, true, clock
+	}
+
+	probe.OK = true
+	probe.Detail = "Reachable"
+	return probe, false, readClock(resp)
+}
+
+func probeClock(clock ClockReading) Probe {
+	probe := Probe{Name: "Clock", OK: !clock.IsSkewed()}
+
+	skew := clock.Skew().Round(time.Second)
+	switch {
+	case skew > 0:
+		probe.Detail = fmt.Sprintf("%s ahead of the network time", skew)
+	case skew < 0:
+		probe.Detail = fmt.Sprintf("%s behind the network time", -skew)
+	default:
+		probe.Detail = "In sync with the network time"
+	}
+
+	return probe
+}
+
+// probeTLS returns the TLS error, if any, encountered connecting to host.
+func probeTLS(host string) (probe Probe, tlsErr error) {
+	probe = Probe{Name: "Secure Connection"}
+
+	client := *httpclient.Default()
+	client.Timeout = 10 * time.Second
+
+	resp, err := client.Head("https://" + host + "/")
+	if err != nil {
+		probe.Detail = err.Error()
+		if isTLSError(err) {
+			return probe, err
+		}
+		return probe, nil
+	}
+	defer resp.Body.Close()
+
+	probe.OK = true
+	probe.Detail = fmt.Sprintf("%s (%s)", host, resp.Status)
+	return probe, nil
+}
@@ -0,0 +1,103 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/UncleJunVIP/nextui-pak-store/database"
+	"github.com/UncleJunVIP/nextui-pak-store/httpclient"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+)
+
+// Export is the file users attach to support requests.
+type Export struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	PakStore    string         `json:"pak_store"`
+	Connection  *ExportReport  `json:"connection,omitempty"`
+	History     []HistoryEntry `json:"history"`
+}
+
+type ExportReport struct {
+	Summary    string  `json:"summary"`
+	Cause      string  `json:"cause,omitempty"`
+	StatusCode int     `json:"status_code,omitempty"`
+	ClockSkew  string  `json:"clock_skew,omitempty"`
+	Probes     []Probe `json:"probes"`
+}
+
+type HistoryEntry struct {
+	PakName     string `json:"pak_name"`
+	RepoURL     string `json:"repo_url,omitempty"`
+	Action      string `json:"action"`
+	FromVersion string `json:"from_version,omitempty"`
+	ToVersion   string `json:"to_version,omitempty"`
+	Succeeded   bool   `json:"succeeded"`
+	Error       string `json:"error,omitempty"`
+	StartedAt   string `json:"started_at"`
+	DurationMs  int64  `json:"duration_ms"`
+}
+
+// WriteExport saves the connection report, when there is one, together with the install history
+// to the Pak Store config directory and returns the path it was written to.
+func WriteExport(report *Report) (string, error) {
+	export := Export{
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+		PakStore:    httpclient.UserAgent(),
+	}
+
+	if report != nil {
+		export.Connection = &ExportReport{
+			Summary:    report.Message(),
+			StatusCode: report.StatusCode,
+			Probes:     report.Probes,
+		}
+
+		if report.Cause != nil {
+			export.Connection.Cause = report.Cause.Error()
+		}
+
+		if report.Clock.Valid() {
+			export.Connection.ClockSkew = report.Clock.Skew().Round(time.Second).String()
+		}
+	}
+
+	history, err := database.DBQ().ListHistory(context.Background(), models.HistoryLimit)
+	if err != nil {
+		return "", fmt.Errorf("unable to read history: %w", err)
+	}
+
+	for _, h := range history {
+		export.History = append(export.History, HistoryEntry{
+			PakName:     h.PakName,
+			RepoURL:     h.RepoUrl.String,
+			Action:      h.Action,
+			FromVersion: h.FromVersion.String,
+			ToVersion:   h.ToVersion.String,
+			Succeeded:   h.Succeeded == 1,
+			Error:       h.Error.String,
+			StartedAt:   h.StartedAt,
+			DurationMs:  h.DurationMs,
+		})
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	dir := models.PakStoreConfigRoot
+	if os.Getenv("ENVIRONMENT") == "DEV" {
+		dir = "."
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf(models.DiagnosticsExportFilePattern, export.GeneratedAt.Format("20060102-150405")))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
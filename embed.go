@@ -1,6 +1,6 @@
 package nextui_pak_store
 
-import _ "embed"
+import "embed"
 
-//go:embed sql/schema.sql
-var DDL string
+//go:embed sql/migrations/*.sql
+var Migrations embed.FS
@@ -0,0 +1,188 @@
+package forge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/UncleJunVIP/nextui-pak-store/httpclient"
+)
+
+const (
+	GitHub = "github"
+	GitLab = "gitlab"
+	Gitea  = "gitea"
+
+	tagRefPrefix    = "refs/tags/"
+	branchRefPrefix = "refs/heads/"
+
+	// releasesPageSize bounds how far back the newest prerelease is searched for
+	releasesPageSize = 30
+)
+
+type Release struct {
+	Tag         string
+	PublishedAt time.Time
+	Assets      []Asset
+}
+
+type Asset struct {
+	Name        string
+	Size        int64
+	SHA256      string
+	DownloadURL string
+}
+
+// Provider abstracts the code forge that hosts a pak repository.
+// Refs are full git refs, e.g. refs/tags/v1.0.0 or refs/heads/main.
+type Provider interface {
+	Name() string
+	LatestRelease() (Release, error)
+	LatestPrerelease() (Release, bool, error)
+	File(ref string, path string) ([]byte, error)
+	License() (string, error)
+	ReleaseAssetURL(tag string, filename string) string
+	RawFileURL(ref string, path string) string
+}
+
+type repository struct {
+	scheme string
+	host   string
+	owner  string
+	name   string
+}
+
+func (r repository) webURL() string {
+	return fmt.Sprintf("%s://%s/%s/%s", r.scheme, r.host, r.owner, r.name)
+}
+
+func (r repository) path() string {
+	return r.owner + "/" + r.name
+}
+
+// ForRepo picks the provider for a repo URL by its host. Self-hosted GitLab or
+// Gitea/Forgejo instances on custom domains can be selected with hint.
+func ForRepo(repoURL string, hint string) (Provider, error) {
+	u, err := url.Parse(strings.TrimSuffix(strings.TrimSpace(repoURL), ".git"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL %s: %w", repoURL, err)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if u.Host == "" || len(parts) < 2 {
+		return nil, fmt.Errorf("invalid repository URL format: %s", repoURL)
+	}
+
+	repo := repository{
+		scheme: u.Scheme,
+		host:   u.Host,
+		owner:  strings.Join(parts[:len(parts)-1], "/"),
+		name:   parts[len(parts)-1],
+	}
+
+	if repo.scheme == "" {
+		repo.scheme = "https"
+	}
+
+	kind := strings.ToLower(hint)
+	if kind == "" {
+		kind = detect(u.Host)
+	}
+
+	switch kind {
+	case GitHub:
+		return githubProvider{repo}, nil
+	case GitLab:
+		return gitlabProvider{repo}, nil
+	case Gitea, "forgejo", "codeberg":
+		return giteaProvider{repo}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported forge for %s", repoURL)
+}
+
+func detect(host string) string {
+	host = strings.ToLower(host)
+
+	switch {
+	case host == "github.com" || host == "www.github.com":
+		return GitHub
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		return GitLab
+	case host == "codeberg.org" || strings.HasPrefix(host, "gitea.") || strings.HasPrefix(host, "forgejo."):
+		return Gitea
+	}
+
+	return ""
+}
+
+func TagRef(tag string) string {
+	return tagRefPrefix + tag
+}
+
+func BranchRef(branch string) string {
+	return branchRefPrefix + branch
+}
+
+func shortRef(ref string) string {
+	if tag, ok := strings.CutPrefix(ref, tagRefPrefix); ok {
+		return tag
+	}
+	if branch, ok := strings.CutPrefix(ref, branchRefPrefix); ok {
+		return branch
+	}
+	return ref
+}
+
+func escapePath(p string) string {
+	segments := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+func get(apiURL string, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request: %w", err)
+	}
+
+	for k, v := range headers {
+		req.Header.Add(k, v)
+	}
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading HTTP response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API error: %s - %s", resp.Status, string(body))
+	}
+
+	return body, nil
+}
+
+func getJSON(apiURL string, headers map[string]string, out interface{}) error {
+	body, err := get(apiURL, headers)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error decoding API response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// giteaProvider covers Gitea and its fork Forgejo, which powers Codeberg.
+type giteaProvider struct {
+	repository
+}
+
+type giteaRelease struct {
+	TagName     string    `json:"tag_name"`
+	PublishedAt time.Time `json:"published_at"`
+	Assets      []struct {
+		Name               string `json:"name"`
+		Size               int64  `json:"size"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+type giteaRepo struct {
+	Licenses []string `json:"licenses"`
+}
+
+func (g giteaProvider) Name() string {
+	return Gitea
+}
+
+func (g giteaProvider) apiURL(format string, args ...interface{}) string {
+	return fmt.Sprintf("%s://%s/api/v1/repos/%s", g.scheme, g.host, g.path()) + fmt.Sprintf(format, args...)
+}
+
+func (g giteaProvider) headers() map[string]string {
+	headers := map[string]string{"Accept": "application/json"}
+	if token := os.Getenv("GITEA_TOKEN"); token != "" {
+		headers["Authorization"] = "token " + token
+	}
+	return headers
+}
+
+// LatestRelease returns the newest published, non-prerelease release. Gitea does not report checksums.
+func (g giteaProvider) LatestRelease() (Release, error) {
+	var r giteaRelease
+	if err := getJSON(g.apiURL("/releases/latest"), g.headers(), &r); err != nil {
+		return Release{}, err
+	}
+
+	if r.TagName == "" {
+		return Release{}, fmt.Errorf("latest release has no tag")
+	}
+
+	return r.release(), nil
+}
+
+// LatestPrerelease returns the newest published prerelease, if there is one.
+func (g giteaProvider) LatestPrerelease() (Release, bool, error) {
+	var releases []giteaRelease
+	if err := getJSON(g.apiURL("/releases?pre-release=true&draft=false&limit=%d", releasesPageSize), g.headers(), &releases); err != nil {
+		return Release{}, false, err
+	}
+
+	for _, r := range releases {
+		if r.TagName != "" {
+			return r.release(), true, nil
+		}
+	}
+
+	return Release{}, false, nil
+}
+
+func (r giteaRelease) release() Release {
+	release := Release{Tag: r.TagName, PublishedAt: r.PublishedAt}
+	for _, a := range r.Assets {
+		release.Assets = append(release.Assets, Asset{
+			Name:        a.Name,
+			Size:        a.Size,
+			DownloadURL: a.BrowserDownloadURL,
+		})
+	}
+
+	return release
+}
+
+func (g giteaProvider) File(ref string, path string) ([]byte, error) {
+	apiURL := g.apiURL("/raw/%s?ref=%s", escapePath(path), url.QueryEscape(shortRef(ref)))
+	return get(apiURL, g.headers())
+}
+
+// License relies on the license detection added in Gitea 1.22. Older instances report none.
+func (g giteaProvider) License() (string, error) {
+	var r giteaRepo
+	if err := getJSON(g.apiURL(""), g.headers(), &r); err != nil {
+		return "", err
+	}
+
+	if len(r.Licenses) == 0 {
+		return "", nil
+	}
+
+	return strings.Join(r.Licenses, ", "), nil
+}
+
+func (g giteaProvider) ReleaseAssetURL(tag string, filename string) string {
+	return g.webURL() + "/releases/download/" + tag + "/" + filename
+}
+
+func (g giteaProvider) RawFileURL(ref string, path string) string {
+	kind := "commit"
+	if strings.HasPrefix(ref, tagRefPrefix) {
+		kind = "tag"
+	} else if strings.HasPrefix(ref, branchRefPrefix) {
+		kind = "branch"
+	}
+
+	return g.webURL() + "/raw/" + kind + "/" + shortRef(ref) + "/" + strings.TrimPrefix(path, "/")
+}
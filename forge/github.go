@@ -0,0 +1,149 @@
+package forge
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+type githubProvider struct {
+	repository
+}
+
+type githubContent struct {
+	Encoding string `json:"encoding"`
+	Content  string `json:"content"`
+}
+
+type githubRelease struct {
+	TagName     string    `json:"tag_name"`
+	PublishedAt time.Time `json:"published_at"`
+	Draft       bool      `json:"draft"`
+	Prerelease  bool      `json:"prerelease"`
+	Assets      []struct {
+		Name               string `json:"name"`
+		Size               int64  `json:"size"`
+		Digest             string `json:"digest"`
+		BrowserDownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+type githubRepo struct {
+	License *struct {
+		SpdxID string `json:"spdx_id"`
+		Name   string `json:"name"`
+	} `json:"license"`
+}
+
+func (g githubProvider) Name() string {
+	return GitHub
+}
+
+func (g githubProvider) apiURL(format string, args ...interface{}) string {
+	return fmt.Sprintf("https://api.github.com/repos/%s/%s", g.owner, g.name) + fmt.Sprintf(format, args...)
+}
+
+func (g githubProvider) headers() map[string]string {
+	headers := map[string]string{"Accept": "application/vnd.github.v3+json"}
+	if token := os.Getenv("GH_TOKEN"); token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+	return headers
+}
+
+// LatestRelease returns the newest published, non-prerelease release.
+// Drafts and prereleases are never returned by this endpoint.
+func (g githubProvider) LatestRelease() (Release, error) {
+	var r githubRelease
+	if err := getJSON(g.apiURL("/releases/latest"), g.headers(), &r); err != nil {
+		return Release{}, err
+	}
+
+	if r.TagName == "" {
+		return Release{}, fmt.Errorf("latest release has no tag")
+	}
+
+	return r.release(), nil
+}
+
+// LatestPrerelease returns the newest published prerelease, if there is one among the recent releases.
+func (g githubProvider) LatestPrerelease() (Release, bool, error) {
+	var releases []githubRelease
+	if err := getJSON(g.apiURL("/releases?per_page=%d", releasesPageSize), g.headers(), &releases); err != nil {
+		return Release{}, false, err
+	}
+
+	for _, r := range releases {
+		if r.Prerelease && !r.Draft && r.TagName != "" {
+			return r.release(), true, nil
+		}
+	}
+
+	return Release{}, false, nil
+}
+
+func (r githubRelease) release() Release {
+	release := Release{Tag: r.TagName, PublishedAt: r.PublishedAt}
+	for _, a := range r.Assets {
+		sha := ""
+		if digest, ok := strings.CutPrefix(a.Digest, "sha256:"); ok {
+			sha = digest
+		}
+
+		release.Assets = append(release.Assets, Asset{
+			Name:        a.Name,
+			Size:        a.Size,
+			SHA256:      sha,
+			DownloadURL: a.BrowserDownloadURL,
+		})
+	}
+
+	return release
+}
+
+func (g githubProvider) File(ref string, path string) ([]byte, error) {
+	var content githubContent
+	apiURL := g.apiURL("/contents/%s?ref=%s", escapePath(path), url.QueryEscape(shortRef(ref)))
+	if err := getJSON(apiURL, g.headers(), &content); err != nil {
+		return nil, err
+	}
+
+	if content.Encoding != "base64" {
+		return nil, fmt.Errorf("unexpected content encoding: %s", content.Encoding)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(content.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding base64 content: %w", err)
+	}
+
+	return data, nil
+}
+
+func (g githubProvider) License() (string, error) {
+	var r githubRepo
+	if err := getJSON(g.apiURL(""), g.headers(), &r); err != nil {
+		return "", err
+	}
+
+	if r.License == nil {
+		return "", nil
+	}
+
+	if r.License.SpdxID != "" && r.License.SpdxID != "NOASSERTION" {
+		return r.License.SpdxID, nil
+	}
+
+	return r.License.Name, nil
+}
+
+func (g githubProvider) ReleaseAssetURL(tag string, filename string) string {
+	return g.webURL() + "/releases/download/" + tag + "/" + filename
+}
+
+func (g githubProvider) RawFileURL(ref string, path string) string {
+	return fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/%s", g.owner, g.name, ref, strings.TrimPrefix(path, "/"))
+}
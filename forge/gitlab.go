@@ -0,0 +1,144 @@
+package forge
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/UncleJunVIP/nextui-pak-store/version"
+)
+
+type gitlabProvider struct {
+	repository
+}
+
+type gitlabRelease struct {
+	TagName    string    `json:"tag_name"`
+	ReleasedAt time.Time `json:"released_at"`
+	Assets     struct {
+		Links []struct {
+			Name           string `json:"name"`
+			URL            string `json:"url"`
+			DirectAssetURL string `json:"direct_asset_url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+type gitlabProject struct {
+	License *struct {
+		Key      string `json:"key"`
+		Name     string `json:"name"`
+		Nickname string `json:"nickname"`
+	} `json:"license"`
+}
+
+func (g gitlabProvider) Name() string {
+	return GitLab
+}
+
+func (g gitlabProvider) apiURL(format string, args ...interface{}) string {
+	return fmt.Sprintf("%s://%s/api/v4/projects/%s", g.scheme, g.host, url.PathEscape(g.path())) + fmt.Sprintf(format, args...)
+}
+
+func (g gitlabProvider) headers() map[string]string {
+	headers := make(map[string]string)
+	if token := os.Getenv("GITLAB_TOKEN"); token != "" {
+		headers["PRIVATE-TOKEN"] = token
+	}
+	return headers
+}
+
+// LatestRelease returns the newest release whose tag is not a prerelease. GitLab has no
+// prerelease flag and its latest release permalink can point at a beta, so the tags decide.
+// GitLab does not report asset sizes or checksums.
+func (g gitlabProvider) LatestRelease() (Release, error) {
+	releases, err := g.releases()
+	if err != nil {
+		return Release{}, err
+	}
+
+	for _, r := range releases {
+		if r.TagName != "" && !version.IsPrerelease(r.TagName) {
+			return r.release(), nil
+		}
+	}
+
+	return Release{}, fmt.Errorf("no stable release found")
+}
+
+// LatestPrerelease returns the newest release whose tag carries a prerelease suffix,
+// e.g. v1.2.0-beta.1.
+func (g gitlabProvider) LatestPrerelease() (Release, bool, error) {
+	releases, err := g.releases()
+	if err != nil {
+		return Release{}, false, err
+	}
+
+	for _, r := range releases {
+		if version.IsPrerelease(r.TagName) {
+			return r.release(), true, nil
+		}
+	}
+
+	return Release{}, false, nil
+}
+
+// releases lists the most recent releases, newest first.
+func (g gitlabProvider) releases() ([]gitlabRelease, error) {
+	var releases []gitlabRelease
+	apiURL := g.apiURL("/releases?order_by=released_at&sort=desc&per_page=%d", releasesPageSize)
+	if err := getJSON(apiURL, g.headers(), &releases); err != nil {
+		return nil, err
+	}
+
+	return releases, nil
+}
+
+func (r gitlabRelease) release() Release {
+	release := Release{Tag: r.TagName, PublishedAt: r.ReleasedAt}
+	for _, l := range r.Assets.Links {
+		dl := l.DirectAssetURL
+		if dl == "" {
+			dl = l.URL
+		}
+
+		release.Assets = append(release.Assets, Asset{Name: l.Name, DownloadURL: dl})
+	}
+
+	return release
+}
+
+func (g gitlabProvider) File(ref string, path string) ([]byte, error) {
+	apiURL := g.apiURL("/repository/files/%s/raw?ref=%s",
+		url.PathEscape(strings.TrimPrefix(path, "/")), url.QueryEscape(shortRef(ref)))
+	return get(apiURL, g.headers())
+}
+
+func (g gitlabProvider) License() (string, error) {
+	var p gitlabProject
+	if err := getJSON(g.apiURL("?license=true"), g.headers(), &p); err != nil {
+		return "", err
+	}
+
+	if p.License == nil {
+		return "", nil
+	}
+
+	if p.License.Nickname != "" {
+		return p.License.Nickname, nil
+	}
+
+	return p.License.Name, nil
+}
+
+// ReleaseAssetURL uses the permanent link GitLab serves for release links whose
+// direct asset path is the file name.
+func (g gitlabProvider) ReleaseAssetURL(tag string, filename string) string {
+	return g.webURL() + "/-/releases/" + url.PathEscape(tag) + "/downloads/" + filename
+}
+
+func (g gitlabProvider) RawFileURL(ref string, path string) string {
+	return g.webURL() + "/-/raw/" + shortRef(ref) + "/" + strings.TrimPrefix(path, "/")
+}
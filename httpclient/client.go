@@ -0,0 +1,166 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultConnectTimeout = 10 * time.Second
+	DefaultReadTimeout    = 30 * time.Second
+)
+
+type Options struct {
+	// ConnectTimeout bounds dialing and the TLS handshake.
+	ConnectTimeout time.Duration
+	// ReadTimeout bounds waiting for response headers and every pause while reading the body,
+	// so large downloads are never cut off while data keeps arriving.
+	ReadTimeout time.Duration
+	// Version is advertised in the User-Agent as Pak Store/<version>.
+	Version string
+	Logger  *slog.Logger
+}
+
+var (
+	mu      sync.RWMutex
+	options = Options{}
+	client  = newClient(options)
+)
+
+// Configure replaces the shared client. Zero values fall back to the defaults.
+func Configure(opts Options) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	options = opts
+	client = newClient(opts)
+}
+
+// Default returns the shared client every request in Pak Store and the storefront builder goes through.
+func Default() *http.Client {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return client
+}
+
+func UserAgent() string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return userAgent(options.Version)
+}
+
+func userAgent(version string) string {
+	if version == "" {
+		version = "dev"
+	}
+	return "Pak Store/" + version
+}
+
+func newClient(opts Options) *http.Client {
+	if opts.ConnectTimeout <= 0 {
+		opts.ConnectTimeout = DefaultConnectTimeout
+	}
+	if opts.ReadTimeout <= 0 {
+		opts.ReadTimeout = DefaultReadTimeout
+	}
+
+	dialer := &net.Dialer{Timeout: opts.ConnectTimeout, KeepAlive: 30 * time.Second}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			return &idleTimeoutConn{Conn: conn, timeout: opts.ReadTimeout}, nil
+		},
+		TLSHandshakeTimeout:   opts.ConnectTimeout,
+		ResponseHeaderTimeout: opts.ReadTimeout,
+		IdleConnTimeout:       90 * time.Second,
+		MaxIdleConns:          16,
+		ForceAttemptHTTP2:     true,
+	}
+
+	return &http.Client{
+		Transport: &loggingTransport{
+			next:      transport,
+			userAgent: userAgent(opts.Version),
+			logger:    opts.Logger,
+		},
+	}
+}
+
+// idleTimeoutConn fails a read that makes no progress for timeout.
+type idleTimeoutConn struct {
+	net.Conn
+	timeout time.Duration
+}
+
+func (c *idleTimeoutConn) Read(b []byte) (int, error) {
+	if err := c.Conn.SetReadDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
+
+type loggingTransport struct {
+	next      http.RoundTripper
+	userAgent string
+	logger    *slog.Logger
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	logger := t.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		logger.Warn("HTTP request failed",
+			"method", req.Method,
+			"url", req.URL.Redacted(),
+			"duration", elapsed,
+			"error", err)
+		return nil, err
+	}
+
+	level := slog.LevelInfo
+	if resp.StatusCode >= http.StatusBadRequest {
+		level = slog.LevelWarn
+	}
+
+	logger.Log(req.Context(), level, "HTTP request completed",
+		"method", req.Method,
+		"url", req.URL.Redacted(),
+		"status", resp.StatusCode,
+		"duration", elapsed)
+
+	return resp, nil
+}
+
+// StatusError reports a response with an unexpected HTTP status.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("HTTP request to %s failed with status code: %d", e.URL, e.StatusCode)
+}
@@ -0,0 +1,18 @@
+//go:build !dev
+
+package mirrors
+
+import (
+	"path/filepath"
+
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+)
+
+func userMirrorsPath() string {
+	return filepath.Join(models.PakStoreConfigRoot, models.MirrorsFilename)
+}
+
+// localStorefront is only available in dev builds.
+func localStorefront() (models.Storefront, bool, error) {
+	return models.Storefront{}, false, nil
+}
@@ -0,0 +1,24 @@
+//go:build dev
+
+package mirrors
+
+import (
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/settings"
+	"github.com/UncleJunVIP/nextui-pak-store/utils"
+)
+
+func userMirrorsPath() string {
+	return models.MirrorsFilename
+}
+
+// localStorefront reads the storefront from the working directory so dev builds never touch the
+// network unless the storefront override setting is set.
+func localStorefront() (models.Storefront, bool, error) {
+	if settings.Get().StorefrontOverride != "" {
+		return models.Storefront{}, false, nil
+	}
+
+	sf, err := utils.LoadLocalStorefront()
+	return sf, true, err
+}
@@ -0,0 +1,254 @@
+package mirrors
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	"github.com/UncleJunVIP/nextui-pak-store/database"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/settings"
+	"github.com/UncleJunVIP/nextui-pak-store/utils"
+)
+
+// FetchStorefront tries every known mirror, healthiest first, and returns the first copy
+// that is at least as new as the newest copy any mirror has served before. The storefront
+// override setting, when set, replaces every mirror.
+func FetchStorefront() (models.Storefront, error) {
+	logger := common.GetLoggerInstance()
+
+	if sf, ok, err := localStorefront(); ok {
+		return sf, err
+	}
+
+	official := settings.Get().StorefrontURLs
+
+	if override := settings.Get().StorefrontOverride; override != "" {
+		logger.Info("Fetching the storefront from the override", "url", override)
+		return utils.FetchStorefrontFrom(override)
+	}
+
+	if err := sync(); err != nil {
+		logger.Warn("Unable to sync storefront mirrors", "error", err)
+	}
+
+	mirrors, err := Ranked()
+	if err != nil || len(mirrors) == 0 {
+		logger.Warn("Unable to read storefront mirrors, using the official list", "error", err)
+		mirrors = nil
+		for i, u := range official {
+			mirrors = append(mirrors, database.StorefrontMirror{Url: u, Priority: int64(i), Builtin: 1})
+		}
+	}
+
+	newest := newestSeen(mirrors)
+
+	var errs []error
+	for _, m := range mirrors {
+		start := time.Now()
+		sf, err := utils.FetchStorefrontFrom(m.Url)
+		latency := time.Since(start)
+
+		if err == nil && sf.GeneratedAt.Before(newest) {
+			err = fmt.Errorf("mirror copy from %s is older than the newest copy seen (%s)",
+				sf.GeneratedAt.Format(time.RFC3339), newest.Format(time.RFC3339))
+		}
+
+		if err != nil {
+			logger.Warn("Storefront mirror failed", "url", m.Url, "error", err, "latency", latency)
+			recordFailure(m, err)
+			errs = append(errs, fmt.Errorf("%s: %w", m.Url, err))
+			continue
+		}
+
+		recordSuccess(m, latency, sf.GeneratedAt)
+		logger.Info("Fetched storefront", "name", sf.Name, "mirror", m.Url, "latency", latency)
+
+		if err := utils.SaveStorefrontSnapshot(sf); err != nil {
+			logger.Warn("Unable to save storefront snapshot", "error", err)
+		}
+
+		return sf, nil
+	}
+
+	return models.Storefront{}, errors.Join(errs...)
+}
+
+// Ranked lists the mirrors by health: fewest consecutive failures first, then mirrors that
+// have answered before ordered by latency, then by configured priority.
+func Ranked() ([]database.StorefrontMirror, error) {
+	mirrors, err := database.DBQ().ListMirrors(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(mirrors, func(a, b database.StorefrontMirror) int {
+		if a.ConsecutiveFailures != b.ConsecutiveFailures {
+			return int(a.ConsecutiveFailures - b.ConsecutiveFailures)
+		}
+
+		aTried, bTried := a.Successes > 0, b.Successes > 0
+		if aTried != bTried {
+			if aTried {
+				return -1
+			}
+			return 1
+		}
+
+		if aTried && a.AvgLatencyMs != b.AvgLatencyMs {
+			return int(a.AvgLatencyMs - b.AvgLatencyMs)
+		}
+
+		return int(a.Priority - b.Priority)
+	})
+
+	return mirrors, nil
+}
+
+// sync makes the database match the mirrors the storefront source setting allows: the official
+// storefronts from the settings table, the ones listed in mirrors.txt, or both. Health history is
+// kept for mirrors that stay.
+func sync() error {
+	logger := common.GetLoggerInstance()
+	ctx := context.Background()
+
+	source := settings.Get().StorefrontSource
+	official := settings.Get().StorefrontURLs
+
+	var urls []string
+	if source != models.StorefrontSources.Mirrors {
+		urls = slices.Clone(official)
+	}
+
+	if source != models.StorefrontSources.Official {
+		user, err := readUserMirrors()
+		if err != nil {
+			return err
+		}
+
+		for _, u := range user {
+			if !slices.Contains(urls, u) {
+				urls = append(urls, u)
+			}
+		}
+	}
+
+	if len(urls) == 0 {
+		logger.Warn("No storefront mirrors are listed in mirrors.txt, using the official list")
+		urls = slices.Clone(official)
+	}
+
+	for i, u := range urls {
+		isBuiltin := int64(0)
+		if slices.Contains(official, u) {
+			isBuiltin = 1
+		}
+
+		err := database.DBQ().UpsertMirror(ctx, database.UpsertMirrorParams{
+			Url:      u,
+			Priority: int64(i),
+			Builtin:  isBuiltin,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	existing, err := database.DBQ().ListMirrors(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range existing {
+		if !slices.Contains(urls, m.Url) {
+			if err := database.DBQ().DeleteMirror(ctx, m.Url); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// readUserMirrors reads one storefront URL per line. Blank lines and lines starting with # are ignored.
+func readUserMirrors() ([]string, error) {
+	file, err := os.Open(userMirrorsPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var urls []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		urls = append(urls, line)
+	}
+
+	return urls, scanner.Err()
+}
+
+func newestSeen(mirrors []database.StorefrontMirror) time.Time {
+	var newest time.Time
+	for _, m := range mirrors {
+		if !m.LastGeneratedAt.Valid {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, m.LastGeneratedAt.String)
+		if err == nil && t.After(newest) {
+			newest = t
+		}
+	}
+	return newest
+}
+
+func recordSuccess(m database.StorefrontMirror, latency time.Duration, generatedAt time.Time) {
+	logger := common.GetLoggerInstance()
+
+	// Smooth the latency so one slow response doesn't demote an otherwise fast mirror
+	avg := latency.Milliseconds()
+	if m.Successes > 0 {
+		avg = (m.AvgLatencyMs*7 + avg*3) / 10
+	}
+
+	generated := m.LastGeneratedAt
+	if !generatedAt.IsZero() {
+		generated = sql.NullString{String: generatedAt.UTC().Format(time.RFC3339), Valid: true}
+	}
+
+	err := database.DBQ().RecordMirrorSuccess(context.Background(), database.RecordMirrorSuccessParams{
+		AvgLatencyMs:    avg,
+		LastSuccess:     sql.NullString{String: time.Now().UTC().Format(time.RFC3339), Valid: true},
+		LastGeneratedAt: generated,
+		Url:             m.Url,
+	})
+	if err != nil {
+		logger.Error("Unable to record mirror success", "error", err, "url", m.Url)
+	}
+}
+
+func recordFailure(m database.StorefrontMirror, cause error) {
+	logger := common.GetLoggerInstance()
+
+	err := database.DBQ().RecordMirrorFailure(context.Background(), database.RecordMirrorFailureParams{
+		LastFailure: sql.NullString{String: time.Now().UTC().Format(time.RFC3339), Valid: true},
+		LastError:   sql.NullString{String: cause.Error(), Valid: true},
+		Url:         m.Url,
+	})
+	if err != nil {
+		logger.Error("Unable to record mirror failure", "error", err, "url", m.Url)
+	}
+}
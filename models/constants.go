@@ -4,11 +4,29 @@ const (
 	PakStoreRepo            = "https://github.com/UncleJunVIP/nextui-pak-store"
 	StorefrontJsonURL       = "https://pak-store.unclejun.vip/storefront.json"
 	StorefrontJsonBackupURL = "https://raw.githubusercontent.com/UncleJunVIP/nextui-pak-store/refs/heads/gh-pages/storefront.json"
-	GitHubRoot              = "https://github.com/"
-	RawGHUC                 = "https://raw.githubusercontent.com/"
-	RefMainStub             = "/refs/heads/main/"
+	PakSchemaURL            = "https://pak-store.unclejun.vip/pak.schema.json"
+	StorefrontSchemaURL     = "https://pak-store.unclejun.vip/storefront.schema.json"
+	DefaultBranch           = "main"
 	PakJsonStub             = "pak.json"
 
+	StorefrontJsonFilename  = "storefront.json"
+	StorefrontIndexFilename = "storefront_index.json"
+	PakDetailsDir           = "paks"
+	MirrorsFilename         = "mirrors.txt"
+
+	PakSchemaFilename        = "pak.schema.json"
+	StorefrontSchemaFilename = "storefront.schema.json"
+
+	StorefrontDiffJsonFilename     = "storefront_diff.json"
+	StorefrontDiffMarkdownFilename = "storefront_diff.md"
+
+	RecentlyUpdatedCategory = "Recently Updated"
+	RecentlyUpdatedLimit    = 15
+
+	HistoryLimit                 = 200
+	DiagnosticsExportFilePattern = "pak-store-diagnostics-%s.json"
+	ProfileFilename              = "pak-store-profile.json"
+
 	PakStoreConfigRoot = "/mnt/SDCARD/.userdata/tg5040/nextui-pak-store"
 	SDRoot             = "/mnt/SDCARD"
 	ToolRoot           = "/mnt/SDCARD/Tools/tg5040"
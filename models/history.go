@@ -0,0 +1,19 @@
+package models
+
+import "qlova.tech/sum"
+
+type HistoryAction struct {
+	Install,
+	Update,
+	Uninstall,
+	Rollback sum.Int[HistoryAction]
+}
+
+var HistoryActionMap = map[sum.Int[HistoryAction]]string{
+	HistoryActions.Install:   "install",
+	HistoryActions.Update:    "update",
+	HistoryActions.Uninstall: "uninstall",
+	HistoryActions.Rollback:  "rollback",
+}
+
+var HistoryActions = sum.Int[HistoryAction]{}.Sum()
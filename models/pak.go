@@ -1,6 +1,10 @@
 package models
 
 import (
+	"slices"
+	"time"
+
+	"github.com/UncleJunVIP/nextui-pak-store/version"
 	"qlova.tech/sum"
 )
 
@@ -12,6 +16,7 @@ type Pak struct {
 	Description     string            `json:"description"`
 	Author          string            `json:"author"`
 	RepoURL         string            `json:"repo_url"`
+	Forge           string            `json:"forge,omitempty"`
 	ReleaseFilename string            `json:"release_filename"`
 	Changelog       map[string]string `json:"changelog"`
 	PreviousNames   []string          `json:"previous_names"`
@@ -23,8 +28,69 @@ type Pak struct {
 	LargePak        bool              `json:"large_pak"`
 	Disabled        bool              `json:"disabled"`
 
-	IsPakZ       bool `json:"-"`
-	CanUninstall bool `json:"-"`
+	// Populated by the storefront builder from the release the pak.json was read from
+	DownloadURL   string    `json:"download_url,omitempty"`
+	ReleaseSize   int64     `json:"release_size,omitempty"`
+	ReleaseSHA256 string    `json:"release_sha256,omitempty"`
+	ReleaseDate   time.Time `json:"release_date,omitzero"`
+	License       string    `json:"license,omitempty"`
+
+	// Newest prerelease, only set when it is newer than the stable release
+	Prerelease *PakRelease `json:"prerelease,omitempty"`
+
+	IsPakZ           bool   `json:"-"`
+	CanUninstall     bool   `json:"-"`
+	IsBeta           bool   `json:"-"`
+	SourceStorefront string `json:"-"` // Where the storefront listing this pak was loaded from
+}
+
+// PakJson is the pak.json an author keeps in their repo. Everything else on Pak is filled in by
+// the storefront builder, from the storefront listing and the release, and cannot be set here.
+type PakJson struct {
+	Name            string            `json:"name"`
+	Version         string            `json:"version"`
+	PakType         sum.Int[PakType]  `json:"type"`
+	Description     string            `json:"description"`
+	Author          string            `json:"author"`
+	RepoURL         string            `json:"repo_url"`
+	ReleaseFilename string            `json:"release_filename"`
+	Changelog       map[string]string `json:"changelog"`
+	Scripts         Scripts           `json:"scripts"`
+	UpdateIgnore    []string          `json:"update_ignore"`
+	Screenshots     []string          `json:"screenshots"`
+	Platforms       []string          `json:"platforms"`
+}
+
+func (pj PakJson) Pak() Pak {
+	return Pak{
+		Name:            pj.Name,
+		Version:         pj.Version,
+		PakType:         pj.PakType,
+		Description:     pj.Description,
+		Author:          pj.Author,
+		RepoURL:         pj.RepoURL,
+		ReleaseFilename: pj.ReleaseFilename,
+		Changelog:       pj.Changelog,
+		Scripts:         pj.Scripts,
+		UpdateIgnore:    pj.UpdateIgnore,
+		Screenshots:     pj.Screenshots,
+		Platforms:       pj.Platforms,
+	}
+}
+
+type ChangelogEntry struct {
+	Version string
+	Notes   string
+}
+
+type PakRelease struct {
+	Version         string            `json:"version"`
+	ReleaseFilename string            `json:"release_filename"`
+	Changelog       map[string]string `json:"changelog,omitempty"`
+	DownloadURL     string            `json:"download_url,omitempty"`
+	ReleaseSize     int64             `json:"release_size,omitempty"`
+	ReleaseSHA256   string            `json:"release_sha256,omitempty"`
+	ReleaseDate     time.Time         `json:"release_date,omitzero"`
 }
 
 type Scripts struct {
@@ -54,6 +120,53 @@ func (p Pak) Value() interface{} {
 	return p
 }
 
+// AsPrerelease returns the pak as offered on the beta channel, or the pak unchanged when it has no prerelease.
+func (p Pak) AsPrerelease() Pak {
+	if p.Prerelease == nil {
+		return p
+	}
+
+	beta := p
+	beta.Version = p.Prerelease.Version
+	beta.ReleaseFilename = p.Prerelease.ReleaseFilename
+	beta.Changelog = p.Prerelease.Changelog
+	beta.DownloadURL = p.Prerelease.DownloadURL
+	beta.ReleaseSize = p.Prerelease.ReleaseSize
+	beta.ReleaseSHA256 = p.Prerelease.ReleaseSHA256
+	beta.ReleaseDate = p.Prerelease.ReleaseDate
+	beta.Prerelease = nil
+	beta.IsBeta = true
+
+	return beta
+}
+
+// SortedChangelog returns every changelog entry, newest version first.
+func (p Pak) SortedChangelog() []ChangelogEntry {
+	entries := make([]ChangelogEntry, 0, len(p.Changelog))
+	for v, notes := range p.Changelog {
+		entries = append(entries, ChangelogEntry{Version: v, Notes: notes})
+	}
+
+	slices.SortFunc(entries, func(a, b ChangelogEntry) int {
+		return version.Compare(b.Version, a.Version)
+	})
+
+	return entries
+}
+
+// ChangelogSince returns the entries newer than installed up to and including the pak's version,
+// newest first. These are the changes an update from installed brings.
+func (p Pak) ChangelogSince(installed string) []ChangelogEntry {
+	var entries []ChangelogEntry
+	for _, e := range p.SortedChangelog() {
+		if version.Compare(e.Version, installed) > 0 && version.Compare(e.Version, p.Version) <= 0 {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
 func (p Pak) HasScripts() bool {
 	return p.Scripts.PostInstall.Path != "" || p.Scripts.PostUpdate.Path != ""
 }
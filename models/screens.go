@@ -9,14 +9,65 @@ type ScreenName struct {
 	PakInfo,
 	DownloadPak,
 	Updates,
-	ManageInstalled sum.Int[ScreenName]
+	ManageInstalled,
+	Diagnostics,
+	AutoUpdate,
+	ReleaseChannels,
+	History,
+	ImportProfile,
+	Settings,
+	Favorites sum.Int[ScreenName]
 }
 
 var ScreenNames = sum.Int[ScreenName]{}.Sum()
 
 type Screen interface {
 	Name() sum.Int[ScreenName]
-	Draw() (value interface{}, exitCode int, e error)
+	Draw() (Navigation, error)
+}
+
+type NavigationAction struct {
+	Push,
+	Replace,
+	Back,
+	SelfUpdated sum.Int[NavigationAction]
+}
+
+var NavigationActions = sum.Int[NavigationAction]{}.Sum()
+
+// Navigation is what a screen returns once it is done drawing: where to go next, and whether
+// installed paks changed so the screens underneath have to be rebuilt from fresh state.
+type Navigation struct {
+	Action  sum.Int[NavigationAction]
+	Current Screen // The screen that was drawn, with its list position, for Push and Replace
+	Next    Screen // The screen to open, for Push
+	Refresh bool
+}
+
+// Push opens next on top of current. Back from next returns to current as it was left.
+func Push(current Screen, next Screen) Navigation {
+	return Navigation{Action: NavigationActions.Push, Current: current, Next: next}
+}
+
+// Replace redraws the current screen, for screens that stay open after an action.
+func Replace(current Screen) Navigation {
+	return Navigation{Action: NavigationActions.Replace, Current: current}
+}
+
+// Back returns to the previous screen. Back from the first screen quits.
+func Back() Navigation {
+	return Navigation{Action: NavigationActions.Back}
+}
+
+// SelfUpdated quits so the updated Pak Store is started next time.
+func SelfUpdated() Navigation {
+	return Navigation{Action: NavigationActions.SelfUpdated}
+}
+
+// WithRefresh marks that installed paks or their settings changed.
+func (n Navigation) WithRefresh() Navigation {
+	n.Refresh = true
+	return n
 }
 
 type ScreenReturn interface {
@@ -0,0 +1,43 @@
+package models
+
+import "qlova.tech/sum"
+
+type StorefrontSource struct {
+	OfficialAndMirrors,
+	Official,
+	Mirrors sum.Int[StorefrontSource]
+}
+
+var StorefrontSourceMap = map[sum.Int[StorefrontSource]]string{
+	StorefrontSources.OfficialAndMirrors: "official_and_mirrors",
+	StorefrontSources.Official:           "official",
+	StorefrontSources.Mirrors:            "mirrors",
+}
+
+var StorefrontSources = sum.Int[StorefrontSource]{}.Sum()
+
+type OfflineBehavior struct {
+	UseLastStorefront,
+	Quit sum.Int[OfflineBehavior]
+}
+
+var OfflineBehaviorMap = map[sum.Int[OfflineBehavior]]string{
+	OfflineBehaviors.UseLastStorefront: "use_last_storefront",
+	OfflineBehaviors.Quit:              "quit",
+}
+
+var OfflineBehaviors = sum.Int[OfflineBehavior]{}.Sum()
+
+type MessageDuration struct {
+	Short,
+	Normal,
+	Long sum.Int[MessageDuration]
+}
+
+var MessageDurationMap = map[sum.Int[MessageDuration]]string{
+	MessageDurations.Short:  "short",
+	MessageDurations.Normal: "normal",
+	MessageDurations.Long:   "long",
+}
+
+var MessageDurations = sum.Int[MessageDuration]{}.Sum()
@@ -1,7 +1,25 @@
 package models
 
+import "time"
+
 type Storefront struct {
-	Name string `json:"name"`
-	URL  string `json:"url"`
-	Paks []Pak  `json:"paks"`
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	GeneratedAt time.Time `json:"generated_at,omitzero"`
+	Paks        []Pak     `json:"paks"`
+}
+
+// StorefrontIndex is the compact form of the storefront. Each entry points at a pak detail
+// document named after the hash of its contents, so unchanged paks can be served from cache.
+type StorefrontIndex struct {
+	Name        string                 `json:"name"`
+	URL         string                 `json:"url"`
+	GeneratedAt time.Time              `json:"generated_at,omitzero"`
+	Paks        []StorefrontIndexEntry `json:"paks"`
+}
+
+type StorefrontIndexEntry struct {
+	RepoURL string `json:"repo_url"`
+	Version string `json:"version"`
+	Hash    string `json:"hash"`
 }
@@ -0,0 +1,42 @@
+package navigation
+
+import "github.com/UncleJunVIP/nextui-pak-store/models"
+
+// Stack holds the screens the user opened, the main menu at the bottom, so Back always returns
+// to the exact screen that was open before.
+type Stack struct {
+	screens []models.Screen
+}
+
+func NewStack(root models.Screen) *Stack {
+	return &Stack{screens: []models.Screen{root}}
+}
+
+func (s *Stack) Current() models.Screen {
+	return s.screens[len(s.screens)-1]
+}
+
+// Apply moves the stack as the navigation asks. It reports false when Back leaves the first screen.
+func (s *Stack) Apply(n models.Navigation) bool {
+	switch n.Action {
+	case models.NavigationActions.Push:
+		s.screens[len(s.screens)-1] = n.Current
+		s.screens = append(s.screens, n.Next)
+	case models.NavigationActions.Replace:
+		s.screens[len(s.screens)-1] = n.Current
+	case models.NavigationActions.Back:
+		if len(s.screens) == 1 {
+			return false
+		}
+		s.screens = s.screens[:len(s.screens)-1]
+	}
+
+	return true
+}
+
+// Rebuild replaces every screen on the stack with the result of fn, used after installed paks change.
+func (s *Stack) Rebuild(fn func(models.Screen) models.Screen) {
+	for i, screen := range s.screens {
+		s.screens[i] = fn(screen)
+	}
+}
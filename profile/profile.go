@@ -0,0 +1,240 @@
+package profile
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/UncleJunVIP/nextui-pak-store/database"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/settings"
+	"github.com/UncleJunVIP/nextui-pak-store/utils"
+)
+
+// FormatVersion is bumped whenever the profile file changes in a way older Pak Stores cannot read.
+const FormatVersion = 1
+
+// Profile is the list of installed paks, with their settings, that can be carried to another device.
+type Profile struct {
+	Format     int       `json:"format"`
+	ExportedAt time.Time `json:"exported_at"`
+	ExportedBy string    `json:"exported_by,omitempty"`
+	Settings   Settings  `json:"settings"`
+	Paks       []Pak     `json:"paks"`
+}
+
+type Settings struct {
+	PakStoreHold bool              `json:"pak_store_hold"`
+	Preferences  map[string]string `json:"preferences,omitempty"` // As stored in the settings table
+}
+
+// Pak is an installed pak. Hold is set when automatic updates are turned off for it, and Channel
+// is the release channel it updates from.
+type Pak struct {
+	Name    string `json:"name"`
+	RepoURL string `json:"repo_url"`
+	Version string `json:"version"`
+	Hold    bool   `json:"hold"`
+	Channel string `json:"channel"`
+}
+
+const (
+	ChannelStable = "stable"
+	ChannelBeta   = "beta"
+)
+
+// Diff is what importing a profile would change on this device.
+type Diff struct {
+	Missing     []models.Pak            // In the profile and the storefront, not installed
+	Installed   []Pak                   // In the profile and already installed
+	Unavailable []Pak                   // In the profile, not offered by the storefront
+	Extra       []database.InstalledPak // Installed, not in the profile
+}
+
+// Path is where profiles are exported to and imported from, the root of the SD card so the file
+// is easy to find and copy.
+func Path() string {
+	return filepath.Join(utils.GetSDRoot(), models.ProfileFilename)
+}
+
+// Export writes the installed paks and their settings to the profile file and returns its path.
+func Export(pakStoreVersion string) (string, error) {
+	ctx := context.Background()
+
+	installed, err := database.DBQ().ListInstalledPaks(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to read installed paks: %w", err)
+	}
+
+	p := Profile{
+		Format:     FormatVersion,
+		ExportedAt: time.Now().UTC().Truncate(time.Second),
+		ExportedBy: pakStoreVersion,
+		Settings: Settings{
+			Preferences: settings.Get().Values(),
+		},
+	}
+
+	self, err := database.DBQ().GetInstalledPak(ctx, sql.NullString{String: models.PakStoreRepo, Valid: true})
+	if err == nil {
+		p.Settings.PakStoreHold = self.AutoUpdate == 0
+	} else if err != sql.ErrNoRows {
+		return "", fmt.Errorf("unable to read Pak Store settings: %w", err)
+	}
+
+	for _, ip := range installed {
+		if !ip.RepoUrl.Valid || ip.RepoUrl.String == "" {
+			// Paks installed before repo URLs were recorded cannot be found again
+			continue
+		}
+
+		channel := ChannelStable
+		if ip.Beta == 1 {
+			channel = ChannelBeta
+		}
+
+		p.Paks = append(p.Paks, Pak{
+			Name:    ip.DisplayName,
+			RepoURL: ip.RepoUrl.String,
+			Version: ip.Version,
+			Hold:    ip.AutoUpdate == 0,
+			Channel: channel,
+		})
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := Path()
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// Load reads the profile file.
+func Load() (Profile, error) {
+	var p Profile
+
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		return p, err
+	}
+
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("unable to parse profile: %w", err)
+	}
+
+	if p.Format > FormatVersion {
+		return p, fmt.Errorf("profile format %d is newer than this Pak Store supports (%d)", p.Format, FormatVersion)
+	}
+
+	return p, nil
+}
+
+// Compare works out which paks in the profile are missing from this device. Missing paks are
+// installed from the storefront's current release, not the version recorded in the profile.
+func (p Profile) Compare(storefront models.Storefront, installed map[string]database.InstalledPak) Diff {
+	var diff Diff
+
+	listed := make(map[string]bool)
+
+	for _, pp := range p.Paks {
+		listed[pp.RepoURL] = true
+
+		if _, ok := installed[pp.RepoURL]; ok {
+			diff.Installed = append(diff.Installed, pp)
+			continue
+		}
+
+		idx := slices.IndexFunc(storefront.Paks, func(sfp models.Pak) bool {
+			return sfp.RepoURL == pp.RepoURL && !sfp.Disabled
+		})
+
+		if idx == -1 {
+			diff.Unavailable = append(diff.Unavailable, pp)
+			continue
+		}
+
+		diff.Missing = append(diff.Missing, storefront.Paks[idx])
+	}
+
+	for repoURL, ip := range installed {
+		if !listed[repoURL] {
+			diff.Extra = append(diff.Extra, ip)
+		}
+	}
+
+	slices.SortFunc(diff.Missing, func(a, b models.Pak) int {
+		return strings.Compare(a.StorefrontName, b.StorefrontName)
+	})
+	slices.SortFunc(diff.Extra, func(a, b database.InstalledPak) int {
+		return strings.Compare(a.DisplayName, b.DisplayName)
+	})
+
+	return diff
+}
+
+// ApplySettings copies the holds and release channels from the profile onto every pak in it that
+// is installed on this device, and the Pak Store itself, then restores the Pak Store settings.
+// Paks that aren't installed are skipped.
+func (p Profile) ApplySettings() error {
+	ctx := context.Background()
+
+	err := database.WithTx(ctx, func(q *database.Queries) error {
+		err := q.SetAutoUpdate(ctx, database.SetAutoUpdateParams{
+			AutoUpdate: boolToInt(!p.Settings.PakStoreHold),
+			RepoUrl:    sql.NullString{String: models.PakStoreRepo, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, pp := range p.Paks {
+			repoURL := sql.NullString{String: pp.RepoURL, Valid: true}
+
+			err := q.SetAutoUpdate(ctx, database.SetAutoUpdateParams{
+				AutoUpdate: boolToInt(!pp.Hold),
+				RepoUrl:    repoURL,
+			})
+			if err != nil {
+				return err
+			}
+
+			err = q.SetBeta(ctx, database.SetBetaParams{
+				Beta:    boolToInt(pp.Channel == ChannelBeta),
+				RepoUrl: repoURL,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(p.Settings.Preferences) > 0 {
+		return settings.Save(settings.FromValues(p.Settings.Preferences))
+	}
+
+	return nil
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
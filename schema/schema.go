@@ -0,0 +1,275 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+)
+
+const draft = "https://json-schema.org/draft/2020-12/schema"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// enums maps types that are serialized as one of a fixed set of strings to their allowed values.
+var enums = map[reflect.Type][]string{
+	reflect.TypeOf(models.PakTypes.TOOL): pakTypeValues(),
+}
+
+// required lists the fields pak authors must set, matching the README.
+var required = map[reflect.Type][]string{
+	reflect.TypeOf(models.PakJson{}): {"name", "version", "type", "description", "author", "repo_url", "release_filename", "platforms"},
+}
+
+func pakTypeValues() []string {
+	var values []string
+	for _, v := range models.PakTypeMap {
+		values = append(values, v)
+	}
+	slices.Sort(values)
+	return values
+}
+
+// Generate builds a JSON Schema describing how v is serialized.
+func Generate(id string, title string, v interface{}) map[string]interface{} {
+	s := typeSchema(reflect.TypeOf(v))
+	if properties, ok := s["properties"].(map[string]interface{}); ok {
+		// Lets authors point their editor at the schema from inside the document
+		properties["$schema"] = map[string]interface{}{"type": "string"}
+	}
+	s["$schema"] = draft
+	s["$id"] = id
+	s["title"] = title
+	return s
+}
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if values, ok := enums[t]; ok {
+		return map[string]interface{}{"type": "string", "enum": values}
+	}
+
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		for _, f := range fields(t) {
+			properties[f.name] = typeSchema(f.typ)
+		}
+
+		s := map[string]interface{}{
+			"type":                 "object",
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		if r, ok := required[t]; ok {
+			s["required"] = r
+		}
+		return s
+	}
+
+	return map[string]interface{}{}
+}
+
+type field struct {
+	name string
+	typ  reflect.Type
+}
+
+func fields(t reflect.Type) []field {
+	var result []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		result = append(result, field{name: name, typ: f.Type})
+	}
+	return result
+}
+
+// Check compares raw JSON against the shape of v and reports every unknown or mistyped field.
+// encoding/json silently drops unknown keys, so a typo like relase_filename would otherwise
+// just leave the field empty.
+func Check(data []byte, v interface{}) []error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return []error{err}
+	}
+
+	return check("$", doc, reflect.TypeOf(v))
+}
+
+// UnmarshalStrict unmarshals data into v, failing if Check reports any problems.
+func UnmarshalStrict(data []byte, v interface{}) error {
+	if problems := Check(data, v); len(problems) > 0 {
+		return errors.Join(problems...)
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func check(path string, value interface{}, t reflect.Type) []error {
+	if value == nil {
+		return nil
+	}
+
+	if t.Kind() == reflect.Pointer {
+		return check(path, value, t.Elem())
+	}
+
+	mistyped := func(expected string) []error {
+		return []error{fmt.Errorf("%s: expected %s, got %s", path, expected, jsonKind(value))}
+	}
+
+	if values, ok := enums[t]; ok {
+		s, isString := value.(string)
+		if !isString {
+			return mistyped("string")
+		}
+		if !slices.Contains(values, s) {
+			return []error{fmt.Errorf("%s: %q is not one of %s", path, s, strings.Join(values, ", "))}
+		}
+		return nil
+	}
+
+	if t == timeType {
+		s, isString := value.(string)
+		if !isString {
+			return mistyped("date-time string")
+		}
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return []error{fmt.Errorf("%s: %q is not an RFC 3339 date-time", path, s)}
+		}
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			return mistyped("string")
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return mistyped("boolean")
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := value.(json.Number)
+		if !ok {
+			return mistyped("integer")
+		}
+		if _, err := n.Int64(); err != nil {
+			return mistyped("integer")
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := value.(json.Number); !ok {
+			return mistyped("number")
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]interface{})
+		if !ok {
+			return mistyped("array")
+		}
+		var problems []error
+		for i, item := range items {
+			problems = append(problems, check(fmt.Sprintf("%s[%d]", path, i), item, t.Elem())...)
+		}
+		return problems
+	case reflect.Map:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return mistyped("object")
+		}
+		var problems []error
+		for _, k := range sortedKeys(obj) {
+			problems = append(problems, check(path+"."+k, obj[k], t.Elem())...)
+		}
+		return problems
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return mistyped("object")
+		}
+
+		known := make(map[string]reflect.Type)
+		for _, f := range fields(t) {
+			known[f.name] = f.typ
+		}
+
+		var problems []error
+		for _, k := range sortedKeys(obj) {
+			ft, ok := known[k]
+			if !ok && path == "$" && k == "$schema" {
+				continue
+			} else if !ok {
+				problems = append(problems, fmt.Errorf("%s.%s: unknown field", path, k))
+				continue
+			}
+			problems = append(problems, check(path+"."+k, obj[k], ft)...)
+		}
+		return problems
+	}
+
+	return nil
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return "null"
+}
@@ -0,0 +1,189 @@
+package settings
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	"github.com/UncleJunVIP/nextui-pak-store/database"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"qlova.tech/sum"
+)
+
+const (
+	keyStorefrontSource    = "storefront_source"
+	keyBetaByDefault       = "beta_by_default"
+	keyCleanupTempFiles    = "cleanup_temp_files"
+	keyConfirmUninstall    = "confirm_uninstall"
+	keyDownloadConcurrency = "download_concurrency"
+	keyOfflineBehavior     = "offline_behavior"
+	keyMessageDuration     = "message_duration"
+	keyStorefrontURLs      = "storefront_urls"
+	keyStorefrontOverride  = "storefront_override"
+)
+
+// DownloadConcurrencyOptions are the choices offered on the settings screen. Zero uses
+// utils.DefaultParallelDownloads.
+var DownloadConcurrencyOptions = []int{0, 1, 2, 4}
+
+type Settings struct {
+	StorefrontSource    sum.Int[models.StorefrontSource]
+	BetaByDefault       bool // New installs start on the beta channel
+	CleanupTempFiles    bool // Delete downloaded archives after installing and leftovers at launch
+	ConfirmUninstall    bool
+	DownloadConcurrency int
+	OfflineBehavior     sum.Int[models.OfflineBehavior]
+	MessageDuration     sum.Int[models.MessageDuration]
+	StorefrontURLs      []string // Official storefronts, tried alongside the mirrors in mirrors.txt
+	StorefrontOverride  string   // When set, the only storefront fetched. For testing a storefront build.
+}
+
+var current = Defaults()
+
+func Defaults() Settings {
+	return Settings{
+		StorefrontSource:    models.StorefrontSources.OfficialAndMirrors,
+		BetaByDefault:       false,
+		CleanupTempFiles:    true,
+		ConfirmUninstall:    true,
+		DownloadConcurrency: 0,
+		OfflineBehavior:     models.OfflineBehaviors.UseLastStorefront,
+		MessageDuration:     models.MessageDurations.Normal,
+		StorefrontURLs:      []string{models.StorefrontJsonURL, models.StorefrontJsonBackupURL},
+		StorefrontOverride:  "",
+	}
+}
+
+// Get returns the settings read by the last Load or Save.
+func Get() Settings {
+	return current
+}
+
+// Load reads the settings from the database. Settings that were never saved keep their default.
+func Load() error {
+	rows, err := database.DBQ().ListSettings(context.Background())
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string)
+	for _, r := range rows {
+		values[r.Key] = r.Value
+	}
+
+	current = FromValues(values)
+
+	return nil
+}
+
+// Save writes every setting in one transaction and makes them current.
+func Save(s Settings) error {
+	ctx := context.Background()
+
+	err := database.WithTx(ctx, func(q *database.Queries) error {
+		for key, value := range s.Values() {
+			if err := q.SetSetting(ctx, database.SetSettingParams{Key: key, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	current = s
+
+	return nil
+}
+
+// Values encodes the settings the way they are stored in the database and in profiles.
+func (s Settings) Values() map[string]string {
+	return map[string]string{
+		keyStorefrontSource:    models.StorefrontSourceMap[s.StorefrontSource],
+		keyBetaByDefault:       strconv.FormatBool(s.BetaByDefault),
+		keyCleanupTempFiles:    strconv.FormatBool(s.CleanupTempFiles),
+		keyConfirmUninstall:    strconv.FormatBool(s.ConfirmUninstall),
+		keyDownloadConcurrency: strconv.Itoa(s.DownloadConcurrency),
+		keyOfflineBehavior:     models.OfflineBehaviorMap[s.OfflineBehavior],
+		keyMessageDuration:     models.MessageDurationMap[s.MessageDuration],
+		keyStorefrontURLs:      strings.Join(s.StorefrontURLs, "\n"),
+		keyStorefrontOverride:  s.StorefrontOverride,
+	}
+}
+
+// FromValues decodes settings written by Values. Unknown keys are ignored and values that
+// cannot be read fall back to their default.
+func FromValues(values map[string]string) Settings {
+	logger := common.GetLoggerInstance()
+
+	s := Defaults()
+
+	for key, value := range values {
+		ok := true
+
+		switch key {
+		case keyStorefrontSource:
+			s.StorefrontSource, ok = lookup(models.StorefrontSourceMap, value, s.StorefrontSource)
+		case keyBetaByDefault:
+			s.BetaByDefault, ok = parseBool(value, s.BetaByDefault)
+		case keyCleanupTempFiles:
+			s.CleanupTempFiles, ok = parseBool(value, s.CleanupTempFiles)
+		case keyConfirmUninstall:
+			s.ConfirmUninstall, ok = parseBool(value, s.ConfirmUninstall)
+		case keyDownloadConcurrency:
+			n, err := strconv.Atoi(value)
+			if ok = err == nil && n >= 0; ok {
+				s.DownloadConcurrency = n
+			}
+		case keyOfflineBehavior:
+			s.OfflineBehavior, ok = lookup(models.OfflineBehaviorMap, value, s.OfflineBehavior)
+		case keyMessageDuration:
+			s.MessageDuration, ok = lookup(models.MessageDurationMap, value, s.MessageDuration)
+		case keyStorefrontURLs:
+			urls := strings.Fields(value)
+			if ok = len(urls) > 0; ok {
+				s.StorefrontURLs = urls
+			}
+		case keyStorefrontOverride:
+			s.StorefrontOverride = strings.TrimSpace(value)
+		}
+
+		if !ok {
+			logger.Warn("Ignoring invalid setting", "key", key, "value", value)
+		}
+	}
+
+	return s
+}
+
+// MessageDelay scales how long a message stays on screen by the message duration setting.
+func MessageDelay(d time.Duration) time.Duration {
+	switch current.MessageDuration {
+	case models.MessageDurations.Short:
+		return d / 2
+	case models.MessageDurations.Long:
+		return d * 2
+	default:
+		return d
+	}
+}
+
+func lookup[T any](m map[sum.Int[T]]string, value string, fallback sum.Int[T]) (sum.Int[T], bool) {
+	for k, v := range m {
+		if v == value {
+			return k, true
+		}
+	}
+	return fallback, false
+}
+
+func parseBool(value string, fallback bool) (bool, bool) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback, false
+	}
+	return b, true
+}
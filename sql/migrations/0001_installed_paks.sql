@@ -7,4 +7,4 @@ create table installed_paks
     version       text not null,
     can_uninstall int  not null,
     unique (name)
-);
\ No newline at end of file
+);
@@ -0,0 +1,15 @@
+create table storefront_mirrors
+(
+    url                  text not null,
+    priority             int  not null,
+    builtin              int  not null default 0,
+    successes            int  not null default 0,
+    failures             int  not null default 0,
+    consecutive_failures int  not null default 0,
+    avg_latency_ms       int  not null default 0,
+    last_success         text,
+    last_failure         text,
+    last_error           text,
+    last_generated_at    text,
+    unique (url)
+);
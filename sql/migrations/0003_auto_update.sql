@@ -0,0 +1 @@
+alter table installed_paks add column auto_update int not null default 0;
@@ -0,0 +1 @@
+alter table installed_paks add column beta int not null default 0;
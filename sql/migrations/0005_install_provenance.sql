@@ -0,0 +1,7 @@
+alter table installed_paks add column installed_at text;
+alter table installed_paks add column updated_at text;
+alter table installed_paks add column source_storefront text;
+alter table installed_paks add column download_url text;
+alter table installed_paks add column archive_sha256 text;
+alter table installed_paks add column install_paths text;
+alter table installed_paks add column installed_by text;
@@ -0,0 +1,13 @@
+create table history
+(
+    id           integer primary key autoincrement,
+    repo_url     text,
+    pak_name     text not null,
+    action       text not null,
+    from_version text,
+    to_version   text,
+    succeeded    int  not null,
+    error        text,
+    started_at   text not null,
+    duration_ms  int  not null
+);
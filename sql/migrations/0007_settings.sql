@@ -0,0 +1,5 @@
+create table settings
+(
+    key   text primary key,
+    value text not null
+);
@@ -0,0 +1,6 @@
+create table favorites
+(
+    repo_url   text primary key,
+    name       text not null,
+    starred_at text not null
+);
@@ -0,0 +1,4 @@
+insert or ignore into settings (key, value)
+values ('storefront_urls', 'https://pak-store.unclejun.vip/storefront.json
+https://raw.githubusercontent.com/UncleJunVIP/nextui-pak-store/refs/heads/gh-pages/storefront.json'),
+       ('storefront_override', '');
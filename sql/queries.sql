@@ -4,20 +4,58 @@ FROM installed_paks
 WHERE can_uninstall = 1
 ORDER BY name;
 
+-- name: GetInstalledPak :one
+SELECT *
+FROM installed_paks
+WHERE repo_url = ?;
+
 -- name: ListInstalledPaksWithoutRepo :many
 SELECT *
 FROM installed_paks
 WHERE repo_url IS NULL;
 
+-- name: ListAutoUpdatePaks :many
+SELECT *
+FROM installed_paks
+WHERE auto_update = 1
+ORDER BY name;
+
+-- name: SetAutoUpdate :exec
+UPDATE installed_paks
+SET auto_update = ?
+WHERE repo_url = ?;
+
+-- name: SetBeta :exec
+UPDATE installed_paks
+SET beta = ?
+WHERE repo_url = ?;
+
+-- name: SetBetaForAll :exec
+UPDATE installed_paks
+SET beta = ?
+WHERE can_uninstall = 1;
+
 -- name: Install :exec
-INSERT INTO installed_paks (display_name, name, repo_url, version, type, can_uninstall)
-VALUES (?, ?, ?, ?, ?, ?);
+INSERT INTO installed_paks (display_name, name, repo_url, version, type, can_uninstall, installed_at,
+                            source_storefront, download_url, archive_sha256, install_paths, installed_by)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: UpdateVersion :exec
 UPDATE installed_paks
 SET version = ?
 WHERE repo_url = ?;
 
+-- name: RecordUpdate :exec
+UPDATE installed_paks
+SET version           = ?,
+    updated_at        = ?,
+    source_storefront = ?,
+    download_url      = ?,
+    archive_sha256    = ?,
+    install_paths     = ?,
+    installed_by      = ?
+WHERE repo_url = ?;
+
 -- name: Uninstall :exec
 DELETE
 FROM installed_paks
@@ -29,3 +67,70 @@ SET display_name = @new_display_name,
     name         = @new_name,
     repo_url     = @new_repo_url
 WHERE display_name = @old_display_name;
+
+-- name: ListMirrors :many
+SELECT *
+FROM storefront_mirrors
+ORDER BY priority;
+
+-- name: UpsertMirror :exec
+INSERT INTO storefront_mirrors (url, priority, builtin)
+VALUES (?, ?, ?)
+ON CONFLICT (url) DO UPDATE SET priority = excluded.priority,
+                                builtin  = excluded.builtin;
+
+-- name: DeleteMirror :exec
+DELETE
+FROM storefront_mirrors
+WHERE url = ?;
+
+-- name: RecordMirrorSuccess :exec
+UPDATE storefront_mirrors
+SET successes            = successes + 1,
+    consecutive_failures = 0,
+    avg_latency_ms       = @avg_latency_ms,
+    last_success         = @last_success,
+    last_generated_at    = @last_generated_at
+WHERE url = @url;
+
+-- name: RecordMirrorFailure :exec
+UPDATE storefront_mirrors
+SET failures             = failures + 1,
+    consecutive_failures = consecutive_failures + 1,
+    last_failure         = @last_failure,
+    last_error           = @last_error
+WHERE url = @url;
+
+-- name: RecordHistory :exec
+INSERT INTO history (repo_url, pak_name, action, from_version, to_version, succeeded, error, started_at, duration_ms)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListHistory :many
+SELECT *
+FROM history
+ORDER BY id DESC
+LIMIT ?;
+
+-- name: ListSettings :many
+SELECT *
+FROM settings;
+
+-- name: SetSetting :exec
+INSERT INTO settings (key, value)
+VALUES (?, ?)
+ON CONFLICT (key) DO UPDATE SET value = excluded.value;
+
+-- name: AddFavorite :exec
+INSERT INTO favorites (repo_url, name, starred_at)
+VALUES (?, ?, ?)
+ON CONFLICT (repo_url) DO NOTHING;
+
+-- name: RemoveFavorite :exec
+DELETE
+FROM favorites
+WHERE repo_url = ?;
+
+-- name: ListFavorites :many
+SELECT *
+FROM favorites
+ORDER BY name;
@@ -10,10 +10,11 @@ import (
 	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
 	"github.com/UncleJunVIP/nextui-pak-store/database"
 	"github.com/UncleJunVIP/nextui-pak-store/models"
-	"golang.org/x/mod/semver"
+	"github.com/UncleJunVIP/nextui-pak-store/version"
 )
 
-var LastSelectedIndex, LastSelectedPosition int
+// PakStoreVersion is the version of the running Pak Store, recorded with every install
+var PakStoreVersion string
 
 type AppState struct {
 	Storefront          models.Storefront
@@ -22,6 +23,9 @@ type AppState struct {
 	BrowsePaks          map[string]map[string]models.Pak // Sorted by category
 	UpdatesAvailable    []models.Pak
 	UpdatesAvailableMap map[string]models.Pak
+	AutoUpdate          map[string]bool // Keyed by repo URL, includes Pak Store itself
+	AutoUpdatesPending  []models.Pak
+	Favorites           map[string]database.Favorite // Keyed by repo URL
 }
 
 func NewAppState(storefront models.Storefront) AppState {
@@ -90,9 +94,9 @@ func refreshAppState(storefront models.Storefront) AppState {
 				}
 				browsePaks[cat][p.StorefrontName] = p
 			}
-		} else if hasUpdate(installedPaksMap[p.RepoURL].Version, p.Version) {
-			updatesAvailable = append(updatesAvailable, p)
-			updatesAvailableMap[p.RepoURL] = p
+		} else if offered := offeredRelease(p, installedPaksMap[p.RepoURL]); hasUpdate(installedPaksMap[p.RepoURL].Version, offered.Version) {
+			updatesAvailable = append(updatesAvailable, offered)
+			updatesAvailableMap[p.RepoURL] = offered
 		}
 	}
 
@@ -100,6 +104,47 @@ func refreshAppState(storefront models.Storefront) AppState {
 		return strings.Compare(a.StorefrontName, b.StorefrontName)
 	})
 
+	autoUpdate := make(map[string]bool)
+	var autoUpdatesPending []models.Pak
+
+	autoUpdatePaks, err := database.DBQ().ListAutoUpdatePaks(ctx)
+	if err != nil {
+		logger.Error("Unable to read auto-update settings", "error", err)
+	}
+
+	for _, p := range autoUpdatePaks {
+		autoUpdate[p.RepoUrl.String] = true
+
+		for _, sfp := range storefront.Paks {
+			if sfp.RepoURL != p.RepoUrl.String || sfp.Disabled {
+				continue
+			}
+
+			if offered := offeredRelease(sfp, p); hasUpdate(p.Version, offered.Version) {
+				autoUpdatesPending = append(autoUpdatesPending, offered)
+			}
+			break
+		}
+	}
+
+	favorites := make(map[string]database.Favorite)
+
+	starred, err := database.DBQ().ListFavorites(ctx)
+	if err != nil {
+		logger.Error("Unable to read favorites", "error", err)
+	}
+
+	for _, f := range starred {
+		favorites[f.RepoUrl] = f
+	}
+
+	if recent := recentlyUpdated(availablePaks); len(recent) > 0 {
+		browsePaks[models.RecentlyUpdatedCategory] = make(map[string]models.Pak)
+		for _, p := range recent {
+			browsePaks[models.RecentlyUpdatedCategory][p.StorefrontName] = p
+		}
+	}
+
 	delete(installedPaksMap, "Pak Store")
 
 	return AppState{
@@ -109,17 +154,51 @@ func refreshAppState(storefront models.Storefront) AppState {
 		UpdatesAvailableMap: updatesAvailableMap,
 		AvailablePaks:       availablePaks,
 		BrowsePaks:          browsePaks,
+		AutoUpdate:          autoUpdate,
+		AutoUpdatesPending:  autoUpdatesPending,
+		Favorites:           favorites,
 	}
 }
 
-func hasUpdate(installed string, latest string) bool {
-	if !strings.HasPrefix(installed, "v") {
-		installed = "v" + installed
+// recentlyUpdated returns the newest releases among the given paks, newest first. The Pak Store
+// itself is left out, it updates itself and cannot be installed from the storefront.
+func recentlyUpdated(paks []models.Pak) []models.Pak {
+	var recent []models.Pak
+	for _, p := range paks {
+		if !p.Disabled && !p.ReleaseDate.IsZero() && p.RepoURL != models.PakStoreRepo {
+			recent = append(recent, p)
+		}
 	}
 
-	if !strings.HasPrefix(latest, "v") {
-		latest = "v" + latest
+	SortByReleaseDate(recent)
+
+	if len(recent) > models.RecentlyUpdatedLimit {
+		recent = recent[:models.RecentlyUpdatedLimit]
+	}
+
+	return recent
+}
+
+// SortByReleaseDate orders paks newest release first, falling back to the storefront name.
+func SortByReleaseDate(paks []models.Pak) {
+	slices.SortFunc(paks, func(a, b models.Pak) int {
+		if c := b.ReleaseDate.Compare(a.ReleaseDate); c != 0 {
+			return c
+		}
+		return strings.Compare(a.StorefrontName, b.StorefrontName)
+	})
+}
+
+// offeredRelease picks the release an installed pak is offered: the prerelease for paks on the
+// beta channel when it is newer than the stable release, otherwise the stable release.
+func offeredRelease(p models.Pak, installed database.InstalledPak) models.Pak {
+	if installed.Beta == 1 && p.Prerelease != nil && version.Compare(p.Prerelease.Version, p.Version) > 0 {
+		return p.AsPrerelease()
 	}
 
-	return semver.Compare(installed, latest) == -1
+	return p
+}
+
+func hasUpdate(installed string, latest string) bool {
+	return version.IsUpdate(installed, latest)
 }
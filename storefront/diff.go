@@ -0,0 +1,248 @@
+package storefront
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/version"
+)
+
+type PakRef struct {
+	Name    string `json:"name"`
+	RepoURL string `json:"repo_url"`
+	Version string `json:"version"`
+}
+
+type VersionChange struct {
+	Name      string `json:"name"`
+	RepoURL   string `json:"repo_url"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Downgrade bool   `json:"downgrade"`
+}
+
+type MetadataChange struct {
+	Name    string   `json:"name"`
+	RepoURL string   `json:"repo_url"`
+	Fields  []string `json:"fields"`
+}
+
+// Diff describes how a newly built storefront differs from the published one.
+type Diff struct {
+	PreviousCount   int              `json:"previous_count"`
+	CurrentCount    int              `json:"current_count"`
+	Added           []PakRef         `json:"added"`
+	Removed         []PakRef         `json:"removed"`
+	VersionChanges  []VersionChange  `json:"version_changes"`
+	MetadataChanges []MetadataChange `json:"metadata_changes"`
+
+	// Paks whose version cannot be ordered, updates for them are offered on any change
+	UnorderedVersions []PakRef `json:"unordered_versions"`
+}
+
+// ignoredFields are not reported as metadata changes. The version has its own section
+// and changelogs change with every version bump.
+var ignoredFields = []string{"version", "changelog"}
+
+func Compare(previous models.Storefront, current models.Storefront) Diff {
+	diff := Diff{
+		PreviousCount: len(previous.Paks),
+		CurrentCount:  len(current.Paks),
+	}
+
+	prev := make(map[string]models.Pak)
+	for _, p := range previous.Paks {
+		prev[p.RepoURL] = p
+	}
+
+	seen := make(map[string]bool)
+
+	for _, p := range current.Paks {
+		seen[p.RepoURL] = true
+
+		if p.Version != "" && !version.IsValid(p.Version) {
+			diff.UnorderedVersions = append(diff.UnorderedVersions, ref(p))
+		}
+
+		old, ok := prev[p.RepoURL]
+		if !ok {
+			diff.Added = append(diff.Added, ref(p))
+			continue
+		}
+
+		if old.Version != p.Version {
+			diff.VersionChanges = append(diff.VersionChanges, VersionChange{
+				Name:      p.StorefrontName,
+				RepoURL:   p.RepoURL,
+				From:      old.Version,
+				To:        p.Version,
+				Downgrade: isDowngrade(old.Version, p.Version),
+			})
+		}
+
+		if fields := changedFields(old, p); len(fields) > 0 {
+			diff.MetadataChanges = append(diff.MetadataChanges, MetadataChange{
+				Name:    p.StorefrontName,
+				RepoURL: p.RepoURL,
+				Fields:  fields,
+			})
+		}
+	}
+
+	for _, p := range previous.Paks {
+		if !seen[p.RepoURL] {
+			diff.Removed = append(diff.Removed, ref(p))
+		}
+	}
+
+	return diff
+}
+
+func (d Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.VersionChanges) == 0 && len(d.MetadataChanges) == 0
+}
+
+func (d Diff) Downgrades() []VersionChange {
+	var downgrades []VersionChange
+	for _, c := range d.VersionChanges {
+		if c.Downgrade {
+			downgrades = append(downgrades, c)
+		}
+	}
+	return downgrades
+}
+
+// Check returns the reasons the new storefront looks too broken to publish.
+// maxRemovedShare is the largest fraction of previously published paks that may disappear.
+func (d Diff) Check(maxRemovedShare float64) []error {
+	var problems []error
+
+	if d.PreviousCount > 0 {
+		share := float64(len(d.Removed)) / float64(d.PreviousCount)
+		if share > maxRemovedShare {
+			problems = append(problems, fmt.Errorf("%d of %d paks (%.0f%%) would be removed, the limit is %.0f%%",
+				len(d.Removed), d.PreviousCount, share*100, maxRemovedShare*100))
+		}
+	}
+
+	for _, c := range d.Downgrades() {
+		problems = append(problems, fmt.Errorf("%s would go backwards from %s to %s", c.Name, c.From, c.To))
+	}
+
+	return problems
+}
+
+func (d Diff) Markdown() string {
+	var sb strings.Builder
+
+	sb.WriteString("# Storefront Changes\n\n")
+	sb.WriteString(fmt.Sprintf("%d paks published, %d paks in this build.\n", d.PreviousCount, d.CurrentCount))
+
+	if len(d.UnorderedVersions) > 0 {
+		sb.WriteString("\n## Versions That Cannot Be Ordered\n\n")
+		for _, p := range d.UnorderedVersions {
+			sb.WriteString(fmt.Sprintf("- %s %s (%s)\n", p.Name, p.Version, p.RepoURL))
+		}
+	}
+
+	if d.IsEmpty() {
+		sb.WriteString("\nNo changes.\n")
+		return sb.String()
+	}
+
+	if len(d.Added) > 0 {
+		sb.WriteString("\n## Added\n\n")
+		for _, p := range d.Added {
+			sb.WriteString(fmt.Sprintf("- %s %s (%s)\n", p.Name, p.Version, p.RepoURL))
+		}
+	}
+
+	if len(d.Removed) > 0 {
+		sb.WriteString("\n## Removed\n\n")
+		for _, p := range d.Removed {
+			sb.WriteString(fmt.Sprintf("- %s %s (%s)\n", p.Name, p.Version, p.RepoURL))
+		}
+	}
+
+	if len(d.VersionChanges) > 0 {
+		sb.WriteString("\n## Versions\n\n")
+		for _, c := range d.VersionChanges {
+			note := ""
+			if c.Downgrade {
+				note = " **downgrade**"
+			}
+			sb.WriteString(fmt.Sprintf("- %s: %s → %s%s\n", c.Name, c.From, c.To, note))
+		}
+	}
+
+	if len(d.MetadataChanges) > 0 {
+		sb.WriteString("\n## Metadata\n\n")
+		for _, c := range d.MetadataChanges {
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", c.Name, strings.Join(c.Fields, ", ")))
+		}
+	}
+
+	return sb.String()
+}
+
+func ref(p models.Pak) PakRef {
+	return PakRef{Name: p.StorefrontName, RepoURL: p.RepoURL, Version: p.Version}
+}
+
+func isDowngrade(from string, to string) bool {
+	if from == "" || to == "" {
+		return false
+	}
+
+	if !version.IsValid(from) || !version.IsValid(to) {
+		return false
+	}
+
+	return version.Compare(to, from) < 0
+}
+
+// changedFields compares the serialized form of two paks so new fields are covered without changes here.
+func changedFields(a models.Pak, b models.Pak) []string {
+	am, err := fieldMap(a)
+	if err != nil {
+		return nil
+	}
+
+	bm, err := fieldMap(b)
+	if err != nil {
+		return nil
+	}
+
+	var fields []string
+	for k, v := range bm {
+		if slices.Contains(ignoredFields, k) {
+			continue
+		}
+		if !reflect.DeepEqual(am[k], v) {
+			fields = append(fields, k)
+		}
+	}
+	for k := range am {
+		if _, ok := bm[k]; !ok && !slices.Contains(ignoredFields, k) {
+			fields = append(fields, k)
+		}
+	}
+
+	slices.Sort(fields)
+	return fields
+}
+
+func fieldMap(p models.Pak) (map[string]interface{}, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
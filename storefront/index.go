@@ -0,0 +1,43 @@
+package storefront
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+)
+
+// BuildIndex splits a storefront into its compact index and one detail document per pak,
+// keyed by the content hash the index refers to.
+func BuildIndex(sf models.Storefront) (models.StorefrontIndex, map[string][]byte, error) {
+	index := models.StorefrontIndex{
+		Name:        sf.Name,
+		URL:         sf.URL,
+		GeneratedAt: sf.GeneratedAt,
+	}
+	details := make(map[string][]byte)
+
+	for _, p := range sf.Paks {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return index, nil, err
+		}
+
+		hash := Hash(data)
+		details[hash] = data
+
+		index.Paks = append(index.Paks, models.StorefrontIndexEntry{
+			RepoURL: p.RepoURL,
+			Version: p.Version,
+			Hash:    hash,
+		})
+	}
+
+	return index, details, nil
+}
+
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	gaba "github.com/UncleJunVIP/gabagool/pkg/gabagool"
+	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	"github.com/UncleJunVIP/nextui-pak-store/database"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/state"
+	"qlova.tech/sum"
+)
+
+type AutoUpdateScreen struct {
+	AppState state.AppState
+	Position ListPosition
+}
+
+func InitAutoUpdateScreen(appState state.AppState) AutoUpdateScreen {
+	return AutoUpdateScreen{
+		AppState: appState,
+	}
+}
+
+func (aus AutoUpdateScreen) Name() sum.Int[models.ScreenName] {
+	return models.ScreenNames.AutoUpdate
+}
+
+func (aus AutoUpdateScreen) withAppState(appState state.AppState) models.Screen {
+	aus.AppState = appState
+	return aus
+}
+
+// Draw lists the installed paks with their auto-update setting. Selecting a pak toggles it and
+// redraws the list in place.
+func (aus AutoUpdateScreen) Draw() (models.Navigation, error) {
+	logger := common.GetLoggerInstance()
+
+	var menuItems []gaba.MenuItem
+
+	for _, installed := range aus.AppState.InstalledPaks {
+		name := installed.DisplayName
+		for _, p := range aus.AppState.Storefront.Paks {
+			if p.RepoURL == installed.RepoUrl.String {
+				name = p.StorefrontName
+			}
+		}
+
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:     autoUpdateLabel(name, aus.AppState.AutoUpdate[installed.RepoUrl.String]),
+			Selected: false,
+			Focused:  false,
+			Metadata: installed.RepoUrl.String,
+		})
+	}
+
+	slices.SortFunc(menuItems, func(a, b gaba.MenuItem) int {
+		return strings.Compare(a.Text, b.Text)
+	})
+
+	// Pak Store updating itself restarts the app, so it is listed separately and off by default
+	menuItems = append([]gaba.MenuItem{{
+		Text:     autoUpdateLabel("Pak Store (Self-Update)", aus.AppState.AutoUpdate[models.PakStoreRepo]),
+		Selected: false,
+		Focused:  false,
+		Metadata: models.PakStoreRepo,
+	}}, menuItems...)
+
+	options := gaba.DefaultListOptions("Auto-Update at Launch", menuItems)
+	aus.Position.apply(&options, len(menuItems))
+	options.EnableAction = true
+	options.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Toggle"},
+	}
+
+	sel, err := gaba.List(options)
+	if err != nil {
+		return models.Back(), err
+	}
+
+	if sel.IsNone() || sel.Unwrap().SelectedIndex == -1 {
+		return models.Back(), nil
+	}
+
+	aus.Position = positionOf(sel.Unwrap())
+
+	repoURL := sel.Unwrap().SelectedItem.Metadata.(string)
+
+	enabled := int64(1)
+	if aus.AppState.AutoUpdate[repoURL] {
+		enabled = 0
+	}
+
+	err = database.DBQ().SetAutoUpdate(context.Background(), database.SetAutoUpdateParams{
+		AutoUpdate: enabled,
+		RepoUrl:    sql.NullString{String: repoURL, Valid: true},
+	})
+	if err != nil {
+		logger.Error("Unable to save auto-update setting", "error", err, "repo", repoURL)
+		return models.Replace(aus), err
+	}
+
+	return models.Replace(aus).WithRefresh(), nil
+}
+
+func autoUpdateLabel(name string, enabled bool) string {
+	if enabled {
+		return name + ": On"
+	}
+	return name + ": Off"
+}
+
+// RunAutoUpdates installs the pending updates for paks the user opted in to, with one progress
+// view for the downloads and a summary at the end. It reports whether Pak Store updated itself.
+func RunAutoUpdates(paks []models.Pak) (selfUpdated bool) {
+	if len(paks) == 0 {
+		return false
+	}
+
+	return runBatchUpdate("Automatic Updates", paks)
+}
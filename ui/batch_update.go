@@ -0,0 +1,150 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	gaba "github.com/UncleJunVIP/gabagool/pkg/gabagool"
+	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/settings"
+	"github.com/UncleJunVIP/nextui-pak-store/utils"
+)
+
+type UpdateResult struct {
+	Pak models.Pak
+	Err error
+}
+
+// runBatchUpdate downloads every archive up front behind one progress view, installs them one at
+// a time and shows what happened to each pak. Failed paks can be retried from the results screen.
+func runBatchUpdate(title string, paks []models.Pak) (selfUpdated bool) {
+	return runBatch(title, paks, true)
+}
+
+// runBatchInstall is runBatchUpdate for paks that are not installed yet.
+func runBatchInstall(title string, paks []models.Pak) {
+	runBatch(title, paks, false)
+}
+
+func runBatch(title string, paks []models.Pak, isUpdate bool) (selfUpdated bool) {
+	pending := paks
+
+	for len(pending) > 0 {
+		results := installPaks(pending, isUpdate)
+
+		var failed []models.Pak
+		for _, r := range results {
+			if r.Err != nil {
+				failed = append(failed, r.Pak)
+			} else if r.Pak.RepoURL == models.PakStoreRepo {
+				selfUpdated = true
+			}
+		}
+
+		if !showUpdateSummary(title, results, isUpdate) {
+			break
+		}
+
+		pending = failed
+	}
+
+	return selfUpdated
+}
+
+func installPaks(paks []models.Pak, isUpdate bool) (results []UpdateResult) {
+	logger := common.GetLoggerInstance()
+
+	start := time.Now()
+
+	action := models.HistoryActions.Install
+	if isUpdate {
+		action = models.HistoryActions.Update
+	}
+
+	downloads := utils.DownloadPakArchives(paks, settings.Get().DownloadConcurrency)
+
+	noun := "paks"
+	if isUpdate {
+		noun = "updates"
+	}
+
+	gaba.ProcessMessage(fmt.Sprintf("Installing %d %s...", len(downloads), noun), gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+		for _, d := range downloads {
+			result := UpdateResult{Pak: d.Pak, Err: d.Err}
+
+			if result.Err == nil {
+				result.Err = installPak(d.Pak, d.TempFile, isUpdate)
+			} else {
+				recordHistory(action, d.Pak, installedVersion(d.Pak), d.Pak.Version, start, result.Err)
+			}
+
+			if d.TempFile != "" && settings.Get().CleanupTempFiles {
+				_ = os.Remove(d.TempFile)
+			}
+
+			if result.Err != nil {
+				logger.Error("Install failed", "error", result.Err, "pak", d.Pak.StorefrontName, "update", isUpdate)
+			}
+
+			results = append(results, result)
+		}
+		return nil, nil
+	})
+
+	return results
+}
+
+// showUpdateSummary lists the result for every pak. It reports whether the user asked to retry
+// the failed ones, which is only offered when something failed.
+func showUpdateSummary(title string, results []UpdateResult, isUpdate bool) (retry bool) {
+	logger := common.GetLoggerInstance()
+
+	var updated, failed []gaba.MetadataItem
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, gaba.MetadataItem{Label: r.Pak.StorefrontName, Value: r.Err.Error()})
+		} else {
+			updated = append(updated, gaba.MetadataItem{Label: r.Pak.StorefrontName, Value: r.Pak.Version})
+		}
+	}
+
+	done, heading := "installed", "Installed"
+	if isUpdate {
+		done, heading = "updated", "Updated"
+	}
+
+	overview := fmt.Sprintf("%d of %d paks %s.", len(updated), len(results), done)
+	if len(failed) > 0 {
+		overview += fmt.Sprintf(" %d failed.", len(failed))
+	}
+
+	sections := []gaba.Section{gaba.NewDescriptionSection("Overview", overview)}
+	if len(updated) > 0 {
+		sections = append(sections, gaba.NewInfoSection(fmt.Sprintf("%s (%d)", heading, len(updated)), updated))
+	}
+	if len(failed) > 0 {
+		sections = append(sections, gaba.NewInfoSection(fmt.Sprintf("Failed (%d)", len(failed)), failed))
+	}
+
+	options := gaba.DefaultInfoScreenOptions()
+	options.Sections = sections
+	options.ShowThemeBackground = false
+
+	footerItems := []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Continue"},
+	}
+
+	if len(failed) > 0 {
+		footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "X", HelpText: "Retry Failed"})
+	}
+
+	sel, err := gaba.DetailScreen(title, options, footerItems)
+	if err != nil {
+		logger.Error("Unable to display update summary", "error", err)
+		return false
+	}
+
+	return len(failed) > 0 && !sel.IsNone()
+}
@@ -0,0 +1,357 @@
+package ui
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	"github.com/UncleJunVIP/nextui-pak-store/database"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/settings"
+	"github.com/UncleJunVIP/nextui-pak-store/state"
+	"github.com/UncleJunVIP/nextui-pak-store/utils"
+	"qlova.tech/sum"
+)
+
+// BookkeepingError means the database could not record the change, so the files were put back
+// as they were.
+type BookkeepingError struct {
+	Action string
+	Err    error
+}
+
+func (e *BookkeepingError) Error() string {
+	return fmt.Sprintf("unable to record the %s: %v", e.Action, e.Err)
+}
+
+func (e *BookkeepingError) Unwrap() error {
+	return e.Err
+}
+
+// installPak extracts a downloaded archive and records the install or update. The files are put
+// in place first and the database only commits once they are; if it cannot, the previous files
+// are put back so the file tree and the database stay in step.
+func installPak(pak models.Pak, tmp string, isUpdate bool) (err error) {
+	start := time.Now()
+	fromVersion := installedVersion(pak)
+
+	action := models.HistoryActions.Install
+	if isUpdate {
+		action = models.HistoryActions.Update
+	}
+
+	defer func() {
+		recordHistory(action, pak, fromVersion, pak.Version, start, err)
+	}()
+
+	p, err := newProvenance(pak, tmp)
+	if err != nil {
+		return err
+	}
+
+	if pak.ReleaseSHA256 != "" && !strings.EqualFold(p.archiveSHA256.String, pak.ReleaseSHA256) {
+		return fmt.Errorf("archive checksum does not match the storefront")
+	}
+
+	record := func() error {
+		return recordInstall(pak, p, isUpdate)
+	}
+
+	if pak.IsPakZ {
+		return installPakZ(pak, tmp, fromVersion, record)
+	}
+
+	return installPakDirectory(pak, tmp, fromVersion, record)
+}
+
+// installPakDirectory extracts into a staging directory and swaps it in whole, so a failed
+// extraction never touches the installed pak.
+func installPakDirectory(pak models.Pak, tmp string, fromVersion string, record func() error) error {
+	dest := utils.PakDestination(pak)
+	if dest == "" {
+		return fmt.Errorf("unknown location for %s", pak.Name)
+	}
+
+	staged, err := utils.StagePakArchive(pak, tmp)
+	if err != nil {
+		return fmt.Errorf("unable to extract: %w", err)
+	}
+	defer os.RemoveAll(staged)
+
+	previous, err := utils.SwapDirectory(dest, staged)
+	if err != nil {
+		return fmt.Errorf("unable to replace files: %w", err)
+	}
+
+	if err := record(); err != nil {
+		rollbackStart := time.Now()
+		recordHistory(models.HistoryActions.Rollback, pak, pak.Version, fromVersion, rollbackStart, utils.RestoreDirectory(dest, previous))
+		return err
+	}
+
+	if previous != "" {
+		_ = os.RemoveAll(previous)
+	}
+
+	return nil
+}
+
+// installPakZ extracts straight onto the SD card, since a PakZ shares directories like Roms with
+// everything else. The files it overwrites are moved aside first and put back, with the files it
+// added removed, if the extraction or the bookkeeping fails.
+func installPakZ(pak models.Pak, tmp string, fromVersion string, record func() error) error {
+	paths, err := utils.InstallPaths(pak, tmp)
+	if err != nil {
+		return fmt.Errorf("unable to read archive: %w", err)
+	}
+
+	moved, err := utils.MoveFilesAside(pak, paths)
+	if err != nil {
+		return fmt.Errorf("unable to back up files: %w", err)
+	}
+
+	err = utils.ExtractPakArchive(pak, tmp)
+	if err != nil {
+		err = fmt.Errorf("unable to extract: %w", err)
+	} else {
+		err = record()
+	}
+
+	if err != nil {
+		rollbackStart := time.Now()
+		rollbackErr := errors.Join(utils.RemoveFiles(paths), moved.Restore())
+		recordHistory(models.HistoryActions.Rollback, pak, pak.Version, fromVersion, rollbackStart, rollbackErr)
+		return err
+	}
+
+	_ = moved.Discard()
+
+	return nil
+}
+
+// recordInstall writes the install or update to the database in one transaction.
+func recordInstall(pak models.Pak, p provenance, isUpdate bool) error {
+	err := database.WithTx(context.Background(), func(q *database.Queries) error {
+		if isUpdate {
+			return q.RecordUpdate(context.Background(), database.RecordUpdateParams{
+				Version:          pak.Version,
+				UpdatedAt:        p.timestamp,
+				SourceStorefront: p.sourceStorefront,
+				DownloadUrl:      p.downloadURL,
+				ArchiveSha256:    p.archiveSHA256,
+				InstallPaths:     p.installPaths,
+				InstalledBy:      p.installedBy,
+				RepoUrl:          sql.NullString{String: pak.RepoURL, Valid: true},
+			})
+		}
+
+		err := q.Install(context.Background(), database.InstallParams{
+			DisplayName:      pak.StorefrontName,
+			Name:             pak.Name,
+			RepoUrl:          sql.NullString{String: pak.RepoURL, Valid: true},
+			Version:          pak.Version,
+			Type:             models.PakTypeMap[pak.PakType],
+			CanUninstall:     int64(1),
+			InstalledAt:      p.timestamp,
+			SourceStorefront: p.sourceStorefront,
+			DownloadUrl:      p.downloadURL,
+			ArchiveSha256:    p.archiveSHA256,
+			InstallPaths:     p.installPaths,
+			InstalledBy:      p.installedBy,
+		})
+
+		if err == nil && settings.Get().BetaByDefault {
+			err = q.SetBeta(context.Background(), database.SetBetaParams{
+				Beta:    1,
+				RepoUrl: sql.NullString{String: pak.RepoURL, Valid: true},
+			})
+		}
+
+		return err
+	})
+	if err != nil {
+		return &BookkeepingError{Action: bookkeepingAction(isUpdate), Err: err}
+	}
+
+	return nil
+}
+
+// uninstallPak removes the pak's files and its database record together. The files are moved
+// aside first and only deleted once the record is gone; if the database fails they are put back.
+func uninstallPak(pak models.Pak) (err error) {
+	start := time.Now()
+	fromVersion := installedVersion(pak)
+
+	defer func() {
+		recordHistory(models.HistoryActions.Uninstall, pak, fromVersion, "", start, err)
+	}()
+
+	record := func() error {
+		err := database.WithTx(context.Background(), func(q *database.Queries) error {
+			return q.Uninstall(context.Background(), sql.NullString{String: pak.RepoURL, Valid: true})
+		})
+		if err != nil {
+			return &BookkeepingError{Action: "uninstall", Err: err}
+		}
+		return nil
+	}
+
+	if pak.IsPakZ {
+		return uninstallPakZ(pak, record)
+	}
+
+	dest := utils.PakDestination(pak)
+	if dest == "" {
+		return fmt.Errorf("unknown location for %s", pak.Name)
+	}
+
+	removing := dest + ".removing"
+	if err := os.RemoveAll(removing); err != nil {
+		return fmt.Errorf("unable to remove files: %w", err)
+	}
+
+	if err := os.Rename(dest, removing); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("unable to remove files: %w", err)
+	}
+
+	if err := record(); err != nil {
+		_ = os.Rename(removing, dest)
+		return err
+	}
+
+	if err := os.RemoveAll(removing); err != nil {
+		common.GetLoggerInstance().Warn("Unable to delete uninstalled files", "error", err, "path", removing)
+	}
+
+	return nil
+}
+
+// uninstallPakZ removes the files recorded when the PakZ was installed.
+func uninstallPakZ(pak models.Pak, record func() error) error {
+	installed, err := database.DBQ().GetInstalledPak(context.Background(), nullString(pak.RepoURL))
+	if err != nil {
+		return &BookkeepingError{Action: "uninstall", Err: err}
+	}
+
+	noFileList := fmt.Errorf("%s has no record of its files, update it before uninstalling", pak.StorefrontName)
+
+	var paths []string
+	if err := json.Unmarshal([]byte(installed.InstallPaths.String), &paths); err != nil || len(paths) == 0 {
+		return noFileList
+	}
+
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil && info.IsDir() {
+			// Older Pak Stores recorded the top level directories, which may be shared
+			return noFileList
+		}
+	}
+
+	moved, err := utils.MoveFilesAside(pak, paths)
+	if err != nil {
+		return fmt.Errorf("unable to remove files: %w", err)
+	}
+
+	if err := record(); err != nil {
+		_ = moved.Restore()
+		return err
+	}
+
+	if err := moved.Discard(); err != nil {
+		common.GetLoggerInstance().Warn("Unable to delete uninstalled files", "error", err, "pak", pak.StorefrontName)
+	}
+
+	// Moving the files away may have left directories like Tools/tg5040/Foo.pak empty
+	_ = utils.RemoveFiles(paths)
+
+	return nil
+}
+
+// provenance records where an installed pak came from, for support and debugging.
+type provenance struct {
+	timestamp        sql.NullString
+	sourceStorefront sql.NullString
+	downloadURL      sql.NullString
+	archiveSHA256    sql.NullString
+	installPaths     sql.NullString
+	installedBy      sql.NullString
+}
+
+func newProvenance(pak models.Pak, tmp string) (provenance, error) {
+	sha, err := utils.FileSHA256(tmp)
+	if err != nil {
+		return provenance{}, fmt.Errorf("unable to hash archive: %w", err)
+	}
+
+	paths, err := utils.InstallPaths(pak, tmp)
+	if err != nil {
+		return provenance{}, fmt.Errorf("unable to read archive: %w", err)
+	}
+
+	pathsJson, err := json.Marshal(paths)
+	if err != nil {
+		return provenance{}, err
+	}
+
+	downloadURL, _ := utils.PakDownloadURL(pak)
+
+	return provenance{
+		timestamp:        nullString(time.Now().UTC().Format(time.RFC3339)),
+		sourceStorefront: nullString(pak.SourceStorefront),
+		downloadURL:      nullString(downloadURL),
+		archiveSHA256:    nullString(sha),
+		installPaths:     nullString(string(pathsJson)),
+		installedBy:      nullString(state.PakStoreVersion),
+	}, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// recordHistory logs an attempt outside of any transaction, so failures are kept too.
+func recordHistory(action sum.Int[models.HistoryAction], pak models.Pak, fromVersion string, toVersion string, start time.Time, cause error) {
+	logger := common.GetLoggerInstance()
+
+	entry := database.RecordHistoryParams{
+		RepoUrl:     nullString(pak.RepoURL),
+		PakName:     pak.StorefrontName,
+		Action:      models.HistoryActionMap[action],
+		FromVersion: nullString(fromVersion),
+		ToVersion:   nullString(toVersion),
+		Succeeded:   1,
+		StartedAt:   start.UTC().Format(time.RFC3339),
+		DurationMs:  time.Since(start).Milliseconds(),
+	}
+
+	if cause != nil {
+		entry.Succeeded = 0
+		entry.Error = nullString(cause.Error())
+	}
+
+	if err := database.DBQ().RecordHistory(context.Background(), entry); err != nil {
+		logger.Error("Unable to record history", "error", err, "pak", pak.StorefrontName)
+	}
+}
+
+func installedVersion(pak models.Pak) string {
+	installed, err := database.DBQ().GetInstalledPak(context.Background(), nullString(pak.RepoURL))
+	if err != nil {
+		return ""
+	}
+	return installed.Version
+}
+
+func bookkeepingAction(isUpdate bool) string {
+	if isUpdate {
+		return "update"
+	}
+	return "install"
+}
@@ -13,6 +13,7 @@ import (
 
 type BrowseScreen struct {
 	AppState state.AppState
+	Position ListPosition
 }
 
 func InitBrowseScreen(appState state.AppState) BrowseScreen {
@@ -25,7 +26,16 @@ func (bs BrowseScreen) Name() sum.Int[models.ScreenName] {
 	return models.ScreenNames.Browse
 }
 
-func (bs BrowseScreen) Draw() (selection interface{}, exitCode int, e error) {
+func (bs BrowseScreen) withAppState(appState state.AppState) models.Screen {
+	bs.AppState = appState
+	return bs
+}
+
+func (bs BrowseScreen) Draw() (models.Navigation, error) {
+	if len(bs.AppState.BrowsePaks) == 0 {
+		return models.Back(), nil
+	}
+
 	var menuItems []gabagool.MenuItem
 
 	for cat := range bs.AppState.BrowsePaks {
@@ -38,10 +48,16 @@ func (bs BrowseScreen) Draw() (selection interface{}, exitCode int, e error) {
 	}
 
 	slices.SortFunc(menuItems, func(a, b gabagool.MenuItem) int {
+		if a.Metadata == models.RecentlyUpdatedCategory {
+			return -1
+		} else if b.Metadata == models.RecentlyUpdatedCategory {
+			return 1
+		}
 		return strings.Compare(a.Text, b.Text)
 	})
 
 	options := gabagool.DefaultListOptions("Browse Paks", menuItems)
+	bs.Position.apply(&options, len(menuItems))
 	options.EnableAction = true
 	options.FooterHelpItems = []gabagool.FooterHelpItem{
 		{ButtonName: "B", HelpText: "Back"},
@@ -50,12 +66,14 @@ func (bs BrowseScreen) Draw() (selection interface{}, exitCode int, e error) {
 
 	sel, err := gabagool.List(options)
 	if err != nil {
-		return nil, -1, err
+		return models.Back(), err
 	}
 
 	if sel.IsNone() || sel.Unwrap().SelectedIndex == -1 {
-		return nil, 2, nil
+		return models.Back(), nil
 	}
 
-	return sel.Unwrap().SelectedItem.Metadata, 0, nil
+	bs.Position = positionOf(sel.Unwrap())
+
+	return models.Push(bs, InitPakList(bs.AppState, sel.Unwrap().SelectedItem.Metadata.(string))), nil
 }
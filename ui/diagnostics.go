@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	gaba "github.com/UncleJunVIP/gabagool/pkg/gabagool"
+	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	"github.com/UncleJunVIP/nextui-pak-store/diagnostics"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/settings"
+	"qlova.tech/sum"
+)
+
+type DiagnosticsScreen struct {
+	Report diagnostics.Report
+}
+
+func InitDiagnosticsScreen(report diagnostics.Report) DiagnosticsScreen {
+	return DiagnosticsScreen{
+		Report: report,
+	}
+}
+
+func (ds DiagnosticsScreen) Name() sum.Int[models.ScreenName] {
+	return models.ScreenNames.Diagnostics
+}
+
+func (ds DiagnosticsScreen) Draw() (models.Navigation, error) {
+	logger := common.GetLoggerInstance()
+
+	var probes []gaba.MetadataItem
+	for _, p := range ds.Report.Probes {
+		status := "OK"
+		if !p.OK {
+			status = "Failed"
+		}
+
+		probes = append(probes, gaba.MetadataItem{
+			Label: p.Name,
+			Value: status + " - " + p.Detail,
+		})
+	}
+
+	sections := []gaba.Section{
+		gaba.NewDescriptionSection("Summary", ds.Report.Message()),
+		gaba.NewInfoSection("Probes", probes),
+	}
+
+	options := gaba.DefaultInfoScreenOptions()
+	options.Sections = sections
+	options.ShowThemeBackground = false
+
+	footerItems := []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "X", HelpText: "Export"},
+	}
+
+	for {
+		sel, err := gaba.DetailScreen("Connection Diagnostics", options, footerItems)
+		if err != nil {
+			logger.Error("Unable to display diagnostics screen", "error", err)
+			return models.Back(), err
+		}
+
+		if sel.IsNone() {
+			return models.Back(), nil
+		}
+
+		exportDiagnostics(&ds.Report)
+	}
+}
+
+// exportDiagnostics writes the diagnostics export and tells the user where to find it.
+func exportDiagnostics(report *diagnostics.Report) {
+	logger := common.GetLoggerInstance()
+
+	message := "Unable to export diagnostics!"
+
+	path, err := diagnostics.WriteExport(report)
+	if err != nil {
+		logger.Error("Unable to export diagnostics", "error", err)
+	} else {
+		message = fmt.Sprintf("Diagnostics saved to\n%s", path)
+	}
+
+	gaba.ProcessMessage(message, gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+		time.Sleep(settings.MessageDelay(3 * time.Second))
+		return nil, nil
+	})
+}
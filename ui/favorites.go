@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gaba "github.com/UncleJunVIP/gabagool/pkg/gabagool"
+	"github.com/UncleJunVIP/nextui-pak-store/database"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/settings"
+	"github.com/UncleJunVIP/nextui-pak-store/state"
+	"qlova.tech/sum"
+)
+
+type FavoritesScreen struct {
+	AppState state.AppState
+	Position ListPosition
+}
+
+func InitFavoritesScreen(appState state.AppState) FavoritesScreen {
+	return FavoritesScreen{
+		AppState: appState,
+	}
+}
+
+func (fs FavoritesScreen) Name() sum.Int[models.ScreenName] {
+	return models.ScreenNames.Favorites
+}
+
+func (fs FavoritesScreen) withAppState(appState state.AppState) models.Screen {
+	fs.AppState = appState
+	return fs
+}
+
+// Draw lists the starred paks with their install and update status. Selecting one opens it the
+// same way Browse, Available Updates or Manage Installed would.
+func (fs FavoritesScreen) Draw() (models.Navigation, error) {
+	if len(fs.AppState.Favorites) == 0 {
+		return models.Back(), nil
+	}
+
+	favorites, err := database.DBQ().ListFavorites(context.Background())
+	if err != nil {
+		return models.Back(), err
+	}
+
+	var menuItems []gaba.MenuItem
+	for _, f := range favorites {
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:     f.Name + ": " + fs.status(f),
+			Selected: false,
+			Focused:  false,
+			Metadata: f,
+		})
+	}
+
+	options := gaba.DefaultListOptions("Favorites", menuItems)
+	fs.Position.apply(&options, len(menuItems))
+	options.EnableAction = true
+	options.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "View"},
+	}
+
+	sel, err := gaba.List(options)
+	if err != nil {
+		return models.Back(), err
+	}
+
+	if sel.IsNone() || sel.Unwrap().SelectedIndex == -1 {
+		return models.Back(), nil
+	}
+
+	fs.Position = positionOf(sel.Unwrap())
+
+	f := sel.Unwrap().SelectedItem.Metadata.(database.Favorite)
+
+	pak, ok := fs.storefrontPak(f)
+	if !ok {
+		gaba.ProcessMessage(fmt.Sprintf("%s is no longer\nin the Storefront.", f.Name), gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+			time.Sleep(settings.MessageDelay(2 * time.Second))
+			return nil, nil
+		})
+		return models.Replace(fs), nil
+	}
+
+	return models.Push(fs, pakInfoFor(fs.AppState, pak)), nil
+}
+
+func (fs FavoritesScreen) storefrontPak(f database.Favorite) (models.Pak, bool) {
+	for _, p := range fs.AppState.Storefront.Paks {
+		if p.RepoURL == f.RepoUrl && !p.Disabled {
+			return p, true
+		}
+	}
+	return models.Pak{}, false
+}
+
+func (fs FavoritesScreen) status(f database.Favorite) string {
+	if update, ok := fs.AppState.UpdatesAvailableMap[f.RepoUrl]; ok {
+		return "Update " + update.Version
+	}
+
+	if installed, ok := fs.AppState.InstalledPaks[f.RepoUrl]; ok {
+		return "Installed " + installed.Version
+	}
+
+	if _, ok := fs.storefrontPak(f); !ok {
+		return "Unavailable"
+	}
+
+	return "Not Installed"
+}
+
+func setFavorite(pak models.Pak, starred bool) error {
+	if !starred {
+		return database.DBQ().RemoveFavorite(context.Background(), pak.RepoURL)
+	}
+
+	return database.DBQ().AddFavorite(context.Background(), database.AddFavoriteParams{
+		RepoUrl:   pak.RepoURL,
+		Name:      pak.StorefrontName,
+		StarredAt: time.Now().UTC().Format(time.RFC3339),
+	})
+}
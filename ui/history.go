@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gaba "github.com/UncleJunVIP/gabagool/pkg/gabagool"
+	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	"github.com/UncleJunVIP/nextui-pak-store/database"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/settings"
+	"qlova.tech/sum"
+)
+
+type HistoryScreen struct {
+	Position ListPosition
+}
+
+func InitHistoryScreen() HistoryScreen {
+	return HistoryScreen{}
+}
+
+func (hs HistoryScreen) Name() sum.Int[models.ScreenName] {
+	return models.ScreenNames.History
+}
+
+// Draw lists recent installs, updates, uninstalls and rollbacks, newest first. Selecting an entry
+// shows its details, after which the list reopens in place.
+func (hs HistoryScreen) Draw() (models.Navigation, error) {
+	logger := common.GetLoggerInstance()
+
+	history, err := database.DBQ().ListHistory(context.Background(), models.HistoryLimit)
+	if err != nil {
+		logger.Error("Unable to read history", "error", err)
+		return models.Back(), err
+	}
+
+	if len(history) == 0 {
+		gaba.ProcessMessage("Nothing has been installed yet!", gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+			time.Sleep(settings.MessageDelay(2 * time.Second))
+			return nil, nil
+		})
+		return models.Back(), nil
+	}
+
+	var menuItems []gaba.MenuItem
+	for _, h := range history {
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:     historySummary(h),
+			Selected: false,
+			Focused:  false,
+			Metadata: h,
+		})
+	}
+
+	options := gaba.DefaultListOptions("History", menuItems)
+	hs.Position.apply(&options, len(menuItems))
+	options.EnableAction = true
+	options.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Details"},
+	}
+
+	sel, err := gaba.List(options)
+	if err != nil {
+		return models.Back(), err
+	}
+
+	if sel.IsNone() || sel.Unwrap().SelectedIndex == -1 {
+		return models.Back(), nil
+	}
+
+	hs.Position = positionOf(sel.Unwrap())
+
+	showHistoryEntry(sel.Unwrap().SelectedItem.Metadata.(database.History))
+
+	return models.Replace(hs), nil
+}
+
+func showHistoryEntry(h database.History) {
+	logger := common.GetLoggerInstance()
+
+	result := "Succeeded"
+	if h.Succeeded == 0 {
+		result = "Failed"
+	}
+
+	info := []gaba.MetadataItem{
+		{Label: "Action", Value: h.Action},
+		{Label: "Result", Value: result},
+		{Label: "When", Value: historyTime(h)},
+		{Label: "Duration", Value: (time.Duration(h.DurationMs) * time.Millisecond).String()},
+	}
+
+	if h.FromVersion.String != "" {
+		info = append(info, gaba.MetadataItem{Label: "From", Value: h.FromVersion.String})
+	}
+
+	if h.ToVersion.String != "" {
+		info = append(info, gaba.MetadataItem{Label: "To", Value: h.ToVersion.String})
+	}
+
+	sections := []gaba.Section{gaba.NewInfoSection("Details", info)}
+
+	if h.Error.String != "" {
+		sections = append(sections, gaba.NewDescriptionSection("Error", h.Error.String))
+	}
+
+	options := gaba.DefaultInfoScreenOptions()
+	options.Sections = sections
+	options.ShowThemeBackground = false
+
+	footerItems := []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+	}
+
+	if _, err := gaba.DetailScreen(h.PakName, options, footerItems); err != nil {
+		logger.Error("Unable to display history entry", "error", err)
+	}
+}
+
+func historySummary(h database.History) string {
+	status := ""
+	if h.Succeeded == 0 {
+		status = " (Failed)"
+	}
+
+	version := h.ToVersion.String
+	if version == "" {
+		version = h.FromVersion.String
+	}
+
+	return fmt.Sprintf("%s %s %s %s%s", historyTime(h), h.Action, h.PakName, version, status)
+}
+
+func historyTime(h database.History) string {
+	t, err := time.Parse(time.RFC3339, h.StartedAt)
+	if err != nil {
+		return h.StartedAt
+	}
+	return t.Local().Format("Jan 2 15:04")
+}
@@ -2,7 +2,6 @@ package ui
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/UncleJunVIP/gabagool/pkg/gabagool"
 	"github.com/UncleJunVIP/nextui-pak-store/models"
@@ -12,6 +11,7 @@ import (
 
 type MainMenu struct {
 	AppState state.AppState
+	Position ListPosition
 }
 
 func InitMainMenu(appState state.AppState) MainMenu {
@@ -24,7 +24,12 @@ func (m MainMenu) Name() sum.Int[models.ScreenName] {
 	return models.ScreenNames.MainMenu
 }
 
-func (m MainMenu) Draw() (selection interface{}, exitCode int, e error) {
+func (m MainMenu) withAppState(appState state.AppState) models.Screen {
+	m.AppState = appState
+	return m
+}
+
+func (m MainMenu) Draw() (models.Navigation, error) {
 	title := "Pak Store"
 
 	var menuItems []gabagool.MenuItem
@@ -56,7 +61,63 @@ func (m MainMenu) Draw() (selection interface{}, exitCode int, e error) {
 		})
 	}
 
+	if len(m.AppState.Favorites) > 0 {
+		menuItems = append(menuItems, gabagool.MenuItem{
+			Text:     fmt.Sprintf("Favorites (%d)", len(m.AppState.Favorites)),
+			Selected: false,
+			Focused:  false,
+			Metadata: "Favorites",
+		})
+	}
+
+	menuItems = append(menuItems, gabagool.MenuItem{
+		Text:     "Auto-Update",
+		Selected: false,
+		Focused:  false,
+		Metadata: "Auto-Update",
+	})
+
+	if len(m.AppState.InstalledPaks) > 0 {
+		menuItems = append(menuItems, gabagool.MenuItem{
+			Text:     "Release Channels",
+			Selected: false,
+			Focused:  false,
+			Metadata: "Release Channels",
+		})
+	}
+
+	if len(m.AppState.InstalledPaks) > 0 {
+		menuItems = append(menuItems, gabagool.MenuItem{
+			Text:     "Export Profile",
+			Selected: false,
+			Focused:  false,
+			Metadata: "Export Profile",
+		})
+	}
+
+	menuItems = append(menuItems, gabagool.MenuItem{
+		Text:     "Import Profile",
+		Selected: false,
+		Focused:  false,
+		Metadata: "Import Profile",
+	})
+
+	menuItems = append(menuItems, gabagool.MenuItem{
+		Text:     "History",
+		Selected: false,
+		Focused:  false,
+		Metadata: "History",
+	})
+
+	menuItems = append(menuItems, gabagool.MenuItem{
+		Text:     "Settings",
+		Selected: false,
+		Focused:  false,
+		Metadata: "Settings",
+	})
+
 	options := gabagool.DefaultListOptions(title, menuItems)
+	m.Position.apply(&options, len(menuItems))
 	options.EnableAction = true
 	options.FooterHelpItems = []gabagool.FooterHelpItem{
 		{ButtonName: "B", HelpText: "Quit"},
@@ -65,14 +126,38 @@ func (m MainMenu) Draw() (selection interface{}, exitCode int, e error) {
 
 	sel, err := gabagool.List(options)
 	if err != nil {
-		return nil, -1, err
+		return models.Back(), err
 	}
 
 	if sel.IsNone() || sel.Unwrap().SelectedIndex == -1 {
-		return nil, 2, nil
+		return models.Back(), nil
 	}
 
-	trimmedCount := strings.Split(sel.Unwrap().SelectedItem.Text, " (")[0] // TODO clean this up with regex
+	m.Position = positionOf(sel.Unwrap())
+
+	switch sel.Unwrap().SelectedItem.Metadata.(string) {
+	case "Available Updates":
+		return models.Push(m, InitUpdatesScreen(m.AppState)), nil
+	case "Browse":
+		return models.Push(m, InitBrowseScreen(m.AppState)), nil
+	case "Manage Installed":
+		return models.Push(m, InitManageInstalledScreen(m.AppState)), nil
+	case "Favorites":
+		return models.Push(m, InitFavoritesScreen(m.AppState)), nil
+	case "Auto-Update":
+		return models.Push(m, InitAutoUpdateScreen(m.AppState)), nil
+	case "Release Channels":
+		return models.Push(m, InitReleaseChannelsScreen(m.AppState)), nil
+	case "Export Profile":
+		ExportProfile()
+		return models.Replace(m), nil
+	case "Import Profile":
+		return models.Push(m, InitImportProfileScreen(m.AppState)), nil
+	case "History":
+		return models.Push(m, InitHistoryScreen()), nil
+	case "Settings":
+		return models.Push(m, InitSettingsScreen()), nil
+	}
 
-	return trimmedCount, 0, nil
+	return models.Replace(m), nil
 }
@@ -12,6 +12,7 @@ import (
 
 type ManageInstalledScreen struct {
 	AppState state.AppState
+	Position ListPosition
 }
 
 func InitManageInstalledScreen(appState state.AppState) ManageInstalledScreen {
@@ -24,9 +25,14 @@ func (mis ManageInstalledScreen) Name() sum.Int[models.ScreenName] {
 	return models.ScreenNames.ManageInstalled
 }
 
-func (mis ManageInstalledScreen) Draw() (selection interface{}, exitCode int, e error) {
+func (mis ManageInstalledScreen) withAppState(appState state.AppState) models.Screen {
+	mis.AppState = appState
+	return mis
+}
+
+func (mis ManageInstalledScreen) Draw() (models.Navigation, error) {
 	if len(mis.AppState.InstalledPaks) == 0 {
-		return nil, 2, nil
+		return models.Back(), nil
 	}
 
 	var menuItems []gabagool.MenuItem
@@ -53,6 +59,7 @@ func (mis ManageInstalledScreen) Draw() (selection interface{}, exitCode int, e
 	})
 
 	options := gabagool.DefaultListOptions("Manage Installed Paks", menuItems)
+	mis.Position.apply(&options, len(menuItems))
 	options.EnableAction = true
 	options.FooterHelpItems = []gabagool.FooterHelpItem{
 		{ButtonName: "B", HelpText: "Back"},
@@ -61,14 +68,16 @@ func (mis ManageInstalledScreen) Draw() (selection interface{}, exitCode int, e
 
 	sel, err := gabagool.List(options)
 	if err != nil {
-		return nil, -1, err
+		return models.Back(), err
 	}
 
 	if sel.IsNone() || sel.Unwrap().SelectedIndex == -1 {
-		return nil, 2, nil
+		return models.Back(), nil
 	}
 
+	mis.Position = positionOf(sel.Unwrap())
+
 	selectedPak := sel.Unwrap().SelectedItem.Metadata.(models.Pak)
 
-	return selectedPak, 0, nil
+	return models.Push(mis, InitPakInfoScreen([]models.Pak{selectedPak}, false, true, mis.AppState)), nil
 }
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"github.com/UncleJunVIP/gabagool/pkg/gabagool"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/state"
+)
+
+// ListPosition remembers the selected item and how far a list was scrolled, so a list reopens
+// exactly where the user left it.
+type ListPosition struct {
+	SelectedIndex   int
+	VisiblePosition int
+}
+
+// apply restores the position on a list of itemCount items, which may have shrunk since.
+func (lp ListPosition) apply(options *gabagool.ListOptions, itemCount int) {
+	selected := max(0, min(lp.SelectedIndex, itemCount-1))
+
+	options.SelectedIndex = selected
+	options.VisibleStartIndex = max(0, selected-lp.VisiblePosition)
+}
+
+func positionOf(sel gabagool.ListReturn) ListPosition {
+	return ListPosition{
+		SelectedIndex:   sel.SelectedIndex,
+		VisiblePosition: sel.VisiblePosition,
+	}
+}
+
+// refreshable screens show app state and are rebuilt from fresh state when installed paks change.
+type refreshable interface {
+	withAppState(appState state.AppState) models.Screen
+}
+
+// Refresh rebuilds screen from the given app state, keeping its list position. Screens that
+// don't show app state are returned as they are.
+func Refresh(screen models.Screen, appState state.AppState) models.Screen {
+	if r, ok := screen.(refreshable); ok {
+		return r.withAppState(appState)
+	}
+	return screen
+}
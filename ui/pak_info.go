@@ -1,11 +1,11 @@
 package ui
 
 import (
-	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
 	"slices"
 	"strings"
 	"sync"
@@ -15,32 +15,52 @@ import (
 	"github.com/UncleJunVIP/gabagool/pkg/gabagool/constants"
 	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
 	"github.com/UncleJunVIP/nextui-pak-store/database"
+	"github.com/UncleJunVIP/nextui-pak-store/forge"
 	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/settings"
+	"github.com/UncleJunVIP/nextui-pak-store/state"
 	"github.com/UncleJunVIP/nextui-pak-store/utils"
 	"qlova.tech/sum"
 )
 
 type PakInfoScreen struct {
 	Pak         []models.Pak
-	Category    string
 	IsUpdate    bool
 	IsInstalled bool
+	AppState    state.AppState
 }
 
-func InitPakInfoScreen(pak []models.Pak, category string, isUpdate bool, isInstalled bool) PakInfoScreen {
+func InitPakInfoScreen(pak []models.Pak, isUpdate bool, isInstalled bool, appState state.AppState) PakInfoScreen {
 	return PakInfoScreen{
 		Pak:         pak,
-		Category:    category,
 		IsUpdate:    isUpdate,
 		IsInstalled: isInstalled,
+		AppState:    appState,
 	}
 }
 
+// pakInfoFor opens a single pak the way the list it appears in would: as an update when one is
+// available, as an installed pak to uninstall, or as a pak to install.
+func pakInfoFor(appState state.AppState, pak models.Pak) PakInfoScreen {
+	if update, ok := appState.UpdatesAvailableMap[pak.RepoURL]; ok {
+		return InitPakInfoScreen([]models.Pak{update}, true, false, appState)
+	}
+
+	_, installed := appState.InstalledPaks[pak.RepoURL]
+
+	return InitPakInfoScreen([]models.Pak{pak}, false, installed, appState)
+}
+
 func (pi PakInfoScreen) Name() sum.Int[models.ScreenName] {
 	return models.ScreenNames.PakInfo
 }
 
-func (pi PakInfoScreen) Draw() (selection interface{}, exitCode int, e error) {
+func (pi PakInfoScreen) withAppState(appState state.AppState) models.Screen {
+	pi.AppState = appState
+	return pi
+}
+
+func (pi PakInfoScreen) Draw() (models.Navigation, error) {
 	if len(pi.Pak) == 1 {
 		return pi.DrawSingle()
 	}
@@ -48,7 +68,7 @@ func (pi PakInfoScreen) Draw() (selection interface{}, exitCode int, e error) {
 	return pi.DrawMultiple()
 }
 
-func (pi PakInfoScreen) DrawSingle() (selection interface{}, exitCode int, e error) {
+func (pi PakInfoScreen) DrawSingle() (models.Navigation, error) {
 	logger := common.GetLoggerInstance()
 
 	pak := pi.Pak[0]
@@ -69,8 +89,16 @@ func (pi PakInfoScreen) DrawSingle() (selection interface{}, exitCode int, e err
 				wg.Done()
 			}()
 
-			uri := pak.RepoURL + models.RefMainStub + screenshot
-			uri = strings.ReplaceAll(uri, models.GitHubRoot, models.RawGHUC)
+			uri := screenshot
+			if !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+				// Storefronts built before screenshots were pinned to the release tag
+				provider, err := forge.ForRepo(pak.RepoURL, pak.Forge)
+				if err != nil {
+					logger.Error("Unable to resolve screenshot", "error", err, "screenshot", screenshot)
+					return
+				}
+				uri = provider.RawFileURL(forge.BranchRef(models.DefaultBranch), screenshot)
+			}
 
 			downloadedScreenshot, err := utils.DownloadTempFile(uri)
 			if err == nil {
@@ -105,12 +133,16 @@ func (pi PakInfoScreen) DrawSingle() (selection interface{}, exitCode int, e err
 
 	var sections []gaba.Section
 
-	if _, ok := pak.Changelog[pak.Version]; ok && pi.IsUpdate {
-		sections = append(sections,
-			gaba.NewDescriptionSection(
-				fmt.Sprintf("What's new in %s?", pak.Version),
-				pak.Changelog[pak.Version],
-			))
+	installedVersion := pi.AppState.InstalledPaks[pak.RepoURL].Version
+
+	if pi.IsUpdate {
+		if changes := pak.ChangelogSince(installedVersion); len(changes) > 0 {
+			sections = append(sections,
+				gaba.NewDescriptionSection(
+					fmt.Sprintf("What's new since %s?", installedVersion),
+					formatChangelog(changes),
+				))
+		}
 	}
 
 	if pak.Description != "" {
@@ -129,36 +161,30 @@ func (pi PakInfoScreen) DrawSingle() (selection interface{}, exitCode int, e err
 		))
 	}
 
-	sections = append(sections, gaba.NewInfoSection(
-		"Pak Info",
-		[]gaba.MetadataItem{
-			{Label: "Author", Value: pak.Author},
-			{Label: "Version", Value: pak.Version},
-		},
-	))
-
-	var changelog []string
+	sections = append(sections, gaba.NewInfoSection("Pak Info", pi.pakInfo(pak)))
 
-	var versions []string
-	for k, _ := range pak.Changelog {
-		versions = append(versions, k)
-	}
-
-	slices.SortFunc(versions, func(a, b string) int {
-		return strings.Compare(b, a)
-	})
-
-	for _, v := range versions {
-		changelog = append(changelog, fmt.Sprintf("%s: %s", v, pak.Changelog[v]))
+	if installed, ok := pi.AppState.InstalledPaks[pak.RepoURL]; ok && pi.IsInstalled {
+		if items := installationInfo(installed); len(items) > 0 {
+			sections = append(sections, gaba.NewInfoSection("Installation", items))
+		}
 	}
 
-	if len(changelog) > 0 {
+	if changelog := pak.SortedChangelog(); len(changelog) > 0 {
 		sections = append(sections, gaba.NewDescriptionSection(
 			"Changelog",
-			strings.Join(changelog, "\n\n"),
+			formatChangelog(changelog),
 		))
 	}
 
+	related := pi.alsoByAuthor(pak)
+	if len(related) > 0 {
+		var items []gaba.MetadataItem
+		for _, p := range related {
+			items = append(items, gaba.MetadataItem{Label: p.StorefrontName, Value: strings.Join(p.Categories, ", ")})
+		}
+		sections = append(sections, gaba.NewInfoSection("Also by "+pak.Author, items))
+	}
+
 	qrcode, err := utils.CreateTempQRCode(pak.RepoURL, 256)
 	if err == nil {
 		sections = append(sections, gaba.NewImageSection(
@@ -186,22 +212,54 @@ func (pi PakInfoScreen) DrawSingle() (selection interface{}, exitCode int, e err
 		confirmLabel = "Uninstall"
 	}
 
+	_, isFavorite := pi.AppState.Favorites[pak.RepoURL]
+
+	favoriteLabel := "Favorite"
+	if isFavorite {
+		favoriteLabel = "Unfavorite"
+	}
+
 	footerItems := []gaba.FooterHelpItem{
 		{ButtonName: "B", HelpText: "Back"},
-		{ButtonName: "A", HelpText: confirmLabel},
+		{ButtonName: "Y", HelpText: favoriteLabel},
 	}
 
+	if len(related) > 0 {
+		footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "Select", HelpText: "More by Author"})
+	}
+
+	footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "A", HelpText: confirmLabel})
+
 	sel, err := gaba.DetailScreen(pak.StorefrontName, options, footerItems)
 	if err != nil {
 		logger.Error("Unable to display pak info screen", "error", err)
-		return pi.IsUpdate, -1, err
+		return models.Back(), err
 	}
 
 	if sel.IsNone() {
-		return pi.IsUpdate, 2, nil
+		return models.Back(), nil
 	}
 
-	if pi.IsInstalled {
+	switch sel.Unwrap().LastPressedBtn {
+	case constants.VirtualButtonY:
+		if err := setFavorite(pak, !isFavorite); err != nil {
+			logger.Error("Unable to save favorite", "error", err, "pak", pak.StorefrontName)
+			return models.Replace(pi), err
+		}
+		return models.Replace(pi).WithRefresh(), nil
+	case constants.VirtualButtonSelect:
+		if len(related) == 0 {
+			return models.Replace(pi), nil
+		}
+
+		other, ok, err := chooseRelatedPak(pak.Author, related)
+		if err != nil || !ok {
+			return models.Replace(pi), err
+		}
+		return models.Push(pi, pakInfoFor(pi.AppState, other)), nil
+	}
+
+	if pi.IsInstalled && settings.Get().ConfirmUninstall {
 		confirm, err := gaba.ConfirmationMessage(fmt.Sprintf("Are you sure that you want to uninstall\n %s?", pak.Name),
 			[]gaba.FooterHelpItem{
 				{ButtonName: "B", HelpText: "Nevermind"},
@@ -211,109 +269,95 @@ func (pi PakInfoScreen) DrawSingle() (selection interface{}, exitCode int, e err
 			})
 
 		if err != nil {
-			return nil, -1, err
+			return models.Replace(pi), err
 		}
 
 		if confirm.IsNone() {
-			return nil, 12, nil
+			return models.Replace(pi), nil
 		}
+	}
 
+	if pi.IsInstalled {
 		_, err = gaba.ProcessMessage(fmt.Sprintf("%s %s...", "Uninstalling", pak.Name), gaba.ProcessMessageOptions{}, func() (interface{}, error) {
-			pakLocation := ""
-
-			if pak.PakType == models.PakTypes.TOOL {
-				pakLocation = filepath.Join(utils.GetToolRoot(), pak.Name+".pak")
-			} else if pak.PakType == models.PakTypes.EMU {
-				pakLocation = filepath.Join(utils.GetEmulatorRoot(), pak.Name+".pak")
-			}
+			err := uninstallPak(pak)
 
-			err = os.RemoveAll(pakLocation)
-
-			time.Sleep(1750 * time.Millisecond)
+			time.Sleep(settings.MessageDelay(1750 * time.Millisecond))
 
 			return nil, err
 		})
 
 		if err != nil {
-			gaba.ProcessMessage(fmt.Sprintf("Unable to uninstall %s", pak.Name), gaba.ProcessMessageOptions{}, func() (interface{}, error) {
-				time.Sleep(3 * time.Second)
-				return nil, nil
-			})
-			logger.Error("Unable to remove pak", "error", err)
+			logger.Error("Unable to uninstall pak", "error", err, "pak", pak.Name)
+			showBookkeepingFailure(pak, "uninstall", err)
 		}
 
-		ctx := context.Background()
-		err = database.DBQ().Uninstall(ctx, sql.NullString{String: pak.RepoURL, Valid: true})
-		if err != nil {
-			// TODO wtf do I do here?
-		}
-
-		return nil, 86, nil
+		return models.Back().WithRefresh(), nil
 	}
 
+	start := time.Now()
+
 	tmp, completed, err := utils.DownloadPakArchive(pak)
 	if err != nil {
 
 		if err.Error() == "download cancelled by user" {
-			return pi.IsUpdate, 12, nil
+			return models.Replace(pi), nil
 		}
 
-		logger.Error("Unable to download pak archive", "error", err)
-		return pi.IsUpdate, -1, err
-	} else if !completed {
-		return pi.IsUpdate, 12, nil
-	}
-
-	err = utils.UnzipPakArchive(pak, tmp)
-	if err != nil {
-		return pi.IsUpdate, -1, err
-	}
+		action := models.HistoryActions.Install
+		if pi.IsUpdate {
+			action = models.HistoryActions.Update
+		}
+		recordHistory(action, pak, installedVersion, pak.Version, start, err)
 
-	if pak.HasScripts() {
-		if !pi.IsUpdate {
+		logger.Error("Unable to download pak archive", "error", err)
 
-		}
-	}
+		gaba.ProcessMessage("Unable to Download Pak!", gaba.ProcessMessageOptions{ShowThemeBackground: true}, func() (interface{}, error) {
+			time.Sleep(settings.MessageDelay(1750 * time.Millisecond))
+			return nil, nil
+		})
 
-	if !pi.IsUpdate {
-		info := database.InstallParams{
-			DisplayName:  pak.StorefrontName,
-			Name:         pak.Name,
-			Version:      pak.Version,
-			Type:         models.PakTypeMap[pak.PakType],
-			CanUninstall: int64(1),
-		}
-		database.DBQ().Install(context.Background(), info)
-	} else {
-		update := database.UpdateVersionParams{
-			RepoUrl: sql.NullString{String: pak.RepoURL, Valid: true},
-			Version: pak.Version,
-		}
-		database.DBQ().UpdateVersion(context.Background(), update)
+		return models.Replace(pi), nil
+	} else if !completed {
+		return models.Replace(pi), nil
 	}
 
 	action := "Installed"
+	verb := "Installing"
 	if pi.IsUpdate {
 		action = "Updated"
+		verb = "Updating"
+	}
+
+	_, err = gaba.ProcessMessage(fmt.Sprintf("%s %s...", verb, pak.StorefrontName), gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+		if settings.Get().CleanupTempFiles {
+			defer os.Remove(tmp)
+		}
+		return nil, installPak(pak, tmp, pi.IsUpdate)
+	})
+
+	if err != nil {
+		logger.Error("Unable to install pak", "error", err, "pak", pak.StorefrontName)
+		showBookkeepingFailure(pak, bookkeepingAction(pi.IsUpdate), err)
+		return models.Replace(pi), nil
 	}
 
 	if pak.Name == "Pak Store" {
-		return pi.IsUpdate, 23, nil
+		return models.SelfUpdated(), nil
 	}
 
 	gaba.ProcessMessage(fmt.Sprintf("%s %s!", pak.StorefrontName, action), gaba.ProcessMessageOptions{}, func() (interface{}, error) {
-		time.Sleep(3 * time.Second)
+		time.Sleep(settings.MessageDelay(3 * time.Second))
 		return nil, nil
 	})
 
-	return pi.IsUpdate, 0, nil
+	return models.Back().WithRefresh(), nil
 }
 
-func (pi PakInfoScreen) DrawMultiple() (interface{}, int, error) {
+func (pi PakInfoScreen) DrawMultiple() (models.Navigation, error) {
 	logger := common.GetLoggerInstance()
 
 	if len(pi.Pak) == 0 {
-		return pi.IsUpdate, 2, nil
+		return models.Back(), nil
 	}
 
 	var sections []gaba.Section
@@ -332,13 +376,11 @@ func (pi PakInfoScreen) DrawMultiple() (interface{}, int, error) {
 	))
 
 	for _, pak := range pi.Pak {
+		installedVersion := pi.AppState.InstalledPaks[pak.RepoURL].Version
+
 		info := []gaba.MetadataItem{
 			{Label: "Author", Value: pak.Author},
-			{Label: "Current Version", Value: pak.Version},
-		}
-
-		if changelog, ok := pak.Changelog[pak.Version]; ok {
-			info = append(info, gaba.MetadataItem{Label: "Changelog", Value: changelog})
+			{Label: "Version", Value: versionChange(installedVersion, pak.Version)},
 		}
 
 		sections = append(sections, gaba.NewInfoSection(
@@ -346,6 +388,12 @@ func (pi PakInfoScreen) DrawMultiple() (interface{}, int, error) {
 			info,
 		))
 
+		if changes := pak.ChangelogSince(installedVersion); len(changes) > 0 {
+			sections = append(sections, gaba.NewDescriptionSection(
+				fmt.Sprintf("What's new in %s", pak.StorefrontName),
+				formatChangelog(changes),
+			))
+		}
 	}
 
 	options := gaba.DefaultInfoScreenOptions()
@@ -362,71 +410,250 @@ func (pi PakInfoScreen) DrawMultiple() (interface{}, int, error) {
 	sel, err := gaba.DetailScreen(title, options, footerItems)
 	if err != nil {
 		logger.Error("Unable to display multi-pak info screen", "error", err)
-		return pi.IsUpdate, -1, err
+		return models.Back(), err
 	}
 
 	if sel.IsNone() {
-		return pi.IsUpdate, 2, nil
+		return models.Back(), nil
 	}
 
-	for _, pak := range pi.Pak {
-		tmp, completed, err := utils.DownloadPakArchive(pak)
-		if err != nil {
-			if err.Error() == "download cancelled by user" {
-				return true, 33, nil
-			}
-			logger.Error("Failed to download pak",
-				"error", err,
-				"pak", pak.StorefrontName)
-			gaba.ProcessMessage(fmt.Sprintf("Failed to download %s", pak.StorefrontName),
-				gaba.ProcessMessageOptions{ShowThemeBackground: true}, func() (interface{}, error) {
-					time.Sleep(2 * time.Second)
-					return nil, nil
-				})
-			continue
-		} else if !completed {
-			return true, 33, nil
+	if runBatchUpdate("Update Results", pi.Pak) {
+		return models.SelfUpdated(), nil
+	}
+
+	return models.Back().WithRefresh(), nil
+}
+
+func formatChangelog(entries []models.ChangelogEntry) string {
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, fmt.Sprintf("%s: %s", e.Version, e.Notes))
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+func versionChange(installed string, available string) string {
+	if installed == "" {
+		return available
+	}
+	return installed + " → " + available
+}
+
+// showBookkeepingFailure tells the user the change failed, and when the database refused it,
+// that nothing was touched on the SD card.
+func showBookkeepingFailure(pak models.Pak, action string, err error) {
+	message := fmt.Sprintf("Unable to %s %s!", action, pak.StorefrontName)
+
+	var be *BookkeepingError
+	if errors.As(err, &be) {
+		message = fmt.Sprintf("Unable to save the %s of %s\nto the Pak Store database.\nNothing was changed.", action, pak.StorefrontName)
+	}
+
+	gaba.ProcessMessage(message, gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+		time.Sleep(settings.MessageDelay(3 * time.Second))
+		return nil, nil
+	})
+}
+
+const maxListedPaths = 5
+
+// installationInfo describes where an installed pak came from. Paks installed before this
+// was recorded have none of it.
+func installationInfo(installed database.InstalledPak) []gaba.MetadataItem {
+	var items []gaba.MetadataItem
+
+	add := func(label string, value sql.NullString) {
+		if value.Valid && value.String != "" {
+			items = append(items, gaba.MetadataItem{Label: label, Value: value.String})
 		}
+	}
 
-		err = utils.UnzipPakArchive(pak, tmp)
-		if err != nil {
-			logger.Error("Failed to extract pak",
-				"error", err,
-				"pak", pak.StorefrontName)
-			gaba.ProcessMessage(fmt.Sprintf("Failed to extract %s", pak.StorefrontName),
-				gaba.ProcessMessageOptions{ShowThemeBackground: true}, func() (interface{}, error) {
-					time.Sleep(2 * time.Second)
-					return nil, nil
-				})
-			continue
+	addTime := func(label string, value sql.NullString) {
+		if t, err := time.Parse(time.RFC3339, value.String); err == nil {
+			items = append(items, gaba.MetadataItem{Label: label, Value: t.Local().Format("Jan 2, 2006 15:04")})
 		}
+	}
 
-		update := database.UpdateVersionParams{
-			RepoUrl: sql.NullString{String: pak.RepoURL, Valid: true},
-			Version: pak.Version,
+	addTime("Installed", installed.InstalledAt)
+	if installed.InstalledBy.String != "" {
+		items = append(items, gaba.MetadataItem{Label: "Installed By", Value: "Pak Store " + installed.InstalledBy.String})
+	}
+	add("Storefront", installed.SourceStorefront)
+	add("Download", installed.DownloadUrl)
+	add("SHA-256", installed.ArchiveSha256)
+
+	var paths []string
+	if err := json.Unmarshal([]byte(installed.InstallPaths.String), &paths); err == nil && len(paths) > 0 {
+		location := strings.Join(paths, "\n")
+		if len(paths) > maxListedPaths {
+			// A PakZ records every file it installed
+			location = strings.Join(paths[:maxListedPaths], "\n") + fmt.Sprintf("\nand %d more files", len(paths)-maxListedPaths)
 		}
-		err = database.DBQ().UpdateVersion(context.Background(), update)
-		if err != nil {
-			logger.Error("Failed to update pak in database",
-				"error", err,
-				"pak", pak.Name)
+		items = append(items, gaba.MetadataItem{Label: "Location", Value: location})
+	}
+
+	return items
+}
+
+// pakInfo describes the pak for the Pak Info section. Installed paks show the installed version
+// next to the latest one, along with when they were last changed and how much space they use.
+func (pi PakInfoScreen) pakInfo(pak models.Pak) []gaba.MetadataItem {
+	items := []gaba.MetadataItem{
+		{Label: "Author", Value: pak.Author},
+		{Label: "Type", Value: pakTypeLabel(pak)},
+	}
+
+	if len(pak.Categories) > 0 {
+		items = append(items, gaba.MetadataItem{Label: "Categories", Value: strings.Join(pak.Categories, ", ")})
+	}
+
+	if len(pak.Platforms) > 0 {
+		items = append(items, gaba.MetadataItem{Label: "Platforms", Value: strings.Join(pak.Platforms, ", ")})
+	}
+
+	installed, isInstalled := pi.AppState.InstalledPaks[pak.RepoURL]
+
+	if isInstalled {
+		latest := pak.Version
+		if installed.Version == pak.Version {
+			latest += " (up to date)"
 		}
 
-		if pak.Name == "Pak Store" {
-			gaba.ProcessMessage("Pak Store Updated! Restarting...",
-				gaba.ProcessMessageOptions{ShowThemeBackground: true}, func() (interface{}, error) {
-					time.Sleep(2 * time.Second)
-					return nil, nil
-				})
-			return pi.IsUpdate, 23, nil
+		items = append(items,
+			gaba.MetadataItem{Label: "Installed Version", Value: installed.Version},
+			gaba.MetadataItem{Label: "Latest Version", Value: latest},
+		)
+	} else {
+		items = append(items, gaba.MetadataItem{Label: "Version", Value: pak.Version})
+	}
+
+	if pak.IsBeta {
+		items = append(items, gaba.MetadataItem{Label: "Channel", Value: "Beta (prerelease)"})
+	}
+
+	if !pak.ReleaseDate.IsZero() {
+		items = append(items, gaba.MetadataItem{Label: "Released", Value: pak.ReleaseDate.Local().Format("Jan 2, 2006")})
+	}
+
+	if isInstalled {
+		changed := installed.UpdatedAt
+		if !changed.Valid {
+			changed = installed.InstalledAt
+		}
+
+		if t, err := time.Parse(time.RFC3339, changed.String); err == nil {
+			items = append(items, gaba.MetadataItem{Label: "Last Updated", Value: t.Local().Format("Jan 2, 2006 15:04")})
 		}
 	}
 
-	gaba.ProcessMessage("All paks updated successfully!",
-		gaba.ProcessMessageOptions{ShowThemeBackground: true}, func() (interface{}, error) {
-			time.Sleep(2 * time.Second)
-			return nil, nil
+	if pak.ReleaseSize > 0 {
+		items = append(items, gaba.MetadataItem{Label: "Download Size", Value: utils.FormatBytes(pak.ReleaseSize)})
+	}
+
+	// A PakZ shares top level directories with other paks, so its size on the SD card is unknown
+	if isInstalled && !pak.IsPakZ {
+		if size, err := utils.DirSize(utils.PakDestination(pak)); err == nil {
+			items = append(items, gaba.MetadataItem{Label: "Installed Size", Value: utils.FormatBytes(size)})
+		}
+	}
+
+	location := utils.PakDestination(pak)
+	if pak.IsPakZ {
+		location = "SD card root (" + location + ")"
+	}
+	items = append(items, gaba.MetadataItem{Label: "Install Location", Value: location})
+
+	if pak.License != "" {
+		items = append(items, gaba.MetadataItem{Label: "License", Value: pak.License})
+	}
+
+	items = append(items, gaba.MetadataItem{Label: "Scripts", Value: scriptsSummary(pak.Scripts)})
+
+	return items
+}
+
+// alsoByAuthor lists the author's other paks in the storefront, by name.
+func (pi PakInfoScreen) alsoByAuthor(pak models.Pak) []models.Pak {
+	var related []models.Pak
+
+	if pak.Author == "" {
+		return related
+	}
+
+	for _, p := range pi.AppState.Storefront.Paks {
+		if p.Author == pak.Author && p.RepoURL != pak.RepoURL && p.RepoURL != models.PakStoreRepo && !p.Disabled {
+			related = append(related, p)
+		}
+	}
+
+	slices.SortFunc(related, func(a, b models.Pak) int {
+		return strings.Compare(a.StorefrontName, b.StorefrontName)
+	})
+
+	return related
+}
+
+func pakTypeLabel(pak models.Pak) string {
+	label := "Tool"
+	if pak.PakType == models.PakTypes.EMU {
+		label = "Emulator"
+	}
+
+	if pak.IsPakZ {
+		label += " (PakZ)"
+	}
+
+	return label
+}
+
+// scriptsSummary says which scripts the pak runs, so users know before installing.
+func scriptsSummary(scripts models.Scripts) string {
+	var runs []string
+
+	if scripts.PostInstall.Path != "" {
+		runs = append(runs, "after install")
+	}
+	if scripts.PostUpdate.Path != "" {
+		runs = append(runs, "after update")
+	}
+	if scripts.PostUninstall.Path != "" {
+		runs = append(runs, "after uninstall")
+	}
+
+	if len(runs) == 0 {
+		return "None"
+	}
+
+	return "Runs " + strings.Join(runs, ", ")
+}
+
+// chooseRelatedPak lists the author's other paks and returns the one picked, if any.
+func chooseRelatedPak(author string, related []models.Pak) (models.Pak, bool, error) {
+	var menuItems []gaba.MenuItem
+	for _, p := range related {
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:     p.StorefrontName,
+			Selected: false,
+			Focused:  false,
+			Metadata: p,
 		})
+	}
+
+	options := gaba.DefaultListOptions("Also by "+author, menuItems)
+	options.EnableAction = true
+	options.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "View"},
+	}
+
+	sel, err := gaba.List(options)
+	if err != nil {
+		return models.Pak{}, false, err
+	}
+
+	if sel.IsNone() || sel.Unwrap().SelectedIndex == -1 {
+		return models.Pak{}, false, nil
+	}
 
-	return pi.IsUpdate, 0, nil
+	return sel.Unwrap().SelectedItem.Metadata.(models.Pak), true, nil
 }
@@ -13,6 +13,7 @@ import (
 type PakList struct {
 	AppState state.AppState
 	Category string
+	Position ListPosition
 }
 
 func InitPakList(appState state.AppState, category string) PakList {
@@ -26,9 +27,31 @@ func (pl PakList) Name() sum.Int[models.ScreenName] {
 	return models.ScreenNames.PakList
 }
 
-func (pl PakList) Draw() (selection interface{}, exitCode int, e error) {
-	var menuItems []gabagool.MenuItem
+func (pl PakList) withAppState(appState state.AppState) models.Screen {
+	pl.AppState = appState
+	return pl
+}
+
+func (pl PakList) Draw() (models.Navigation, error) {
+	var paks []models.Pak
 	for _, p := range pl.AppState.BrowsePaks[pl.Category] {
+		paks = append(paks, p)
+	}
+
+	if len(paks) == 0 {
+		return models.Back(), nil
+	}
+
+	if pl.Category == models.RecentlyUpdatedCategory {
+		state.SortByReleaseDate(paks)
+	} else {
+		slices.SortFunc(paks, func(a, b models.Pak) int {
+			return strings.Compare(a.StorefrontName, b.StorefrontName)
+		})
+	}
+
+	var menuItems []gabagool.MenuItem
+	for _, p := range paks {
 		menuItems = append(menuItems, gabagool.MenuItem{
 			Text:     p.StorefrontName,
 			Selected: false,
@@ -37,16 +60,8 @@ func (pl PakList) Draw() (selection interface{}, exitCode int, e error) {
 		})
 	}
 
-	slices.SortFunc(menuItems, func(a, b gabagool.MenuItem) int {
-		return strings.Compare(a.Text, b.Text)
-	})
-
 	options := gabagool.DefaultListOptions(pl.Category, menuItems)
-
-	selectedIndex := state.LastSelectedIndex
-
-	options.SelectedIndex = selectedIndex
-	options.VisibleStartIndex = max(0, state.LastSelectedIndex-state.LastSelectedPosition)
+	pl.Position.apply(&options, len(menuItems))
 	options.EnableAction = true
 	options.FooterHelpItems = []gabagool.FooterHelpItem{
 		{ButtonName: "B", HelpText: "Back"},
@@ -55,15 +70,16 @@ func (pl PakList) Draw() (selection interface{}, exitCode int, e error) {
 
 	sel, err := gabagool.List(options)
 	if err != nil {
-		return nil, -1, err
+		return models.Back(), err
 	}
 
 	if sel.IsNone() || sel.Unwrap().SelectedIndex == -1 {
-		return nil, 2, nil
+		return models.Back(), nil
 	}
 
-	state.LastSelectedIndex = sel.Unwrap().SelectedIndex
-	state.LastSelectedPosition = sel.Unwrap().VisiblePosition
+	pl.Position = positionOf(sel.Unwrap())
+
+	pak := sel.Unwrap().SelectedItem.Metadata.(models.Pak)
 
-	return sel.Unwrap().SelectedItem.Metadata, 0, nil
+	return models.Push(pl, InitPakInfoScreen([]models.Pak{pak}, false, false, pl.AppState)), nil
 }
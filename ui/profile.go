@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+
+	gaba "github.com/UncleJunVIP/gabagool/pkg/gabagool"
+	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/profile"
+	"github.com/UncleJunVIP/nextui-pak-store/settings"
+	"github.com/UncleJunVIP/nextui-pak-store/state"
+	"qlova.tech/sum"
+)
+
+type ImportProfileScreen struct {
+	AppState state.AppState
+}
+
+func InitImportProfileScreen(appState state.AppState) ImportProfileScreen {
+	return ImportProfileScreen{
+		AppState: appState,
+	}
+}
+
+func (ips ImportProfileScreen) Name() sum.Int[models.ScreenName] {
+	return models.ScreenNames.ImportProfile
+}
+
+// Draw shows how the exported profile differs from this device. When the user confirms, the
+// missing paks are installed in one batch and the profile's holds and release channels are
+// applied to every pak in it, including those that were already installed.
+func (ips ImportProfileScreen) Draw() (models.Navigation, error) {
+	logger := common.GetLoggerInstance()
+
+	p, err := profile.Load()
+	if err != nil {
+		logger.Error("Unable to load profile", "error", err, "path", profile.Path())
+
+		message := "Unable to read the profile!"
+		if errors.Is(err, fs.ErrNotExist) {
+			message = fmt.Sprintf("No profile found at\n%s", profile.Path())
+		}
+
+		showProfileMessage(message)
+		return models.Back(), nil
+	}
+
+	diff := p.Compare(ips.AppState.Storefront, ips.AppState.InstalledPaks)
+
+	overview := fmt.Sprintf("%d paks in the profile, %d to install.", len(p.Paks), len(diff.Missing))
+	if p.ExportedBy != "" {
+		overview += fmt.Sprintf("\nExported %s by Pak Store %s.", p.ExportedAt.Local().Format("2006-01-02 15:04"), p.ExportedBy)
+	}
+
+	sections := []gaba.Section{gaba.NewDescriptionSection("Overview", overview)}
+
+	if len(diff.Missing) > 0 {
+		var items []gaba.MetadataItem
+		for _, pak := range diff.Missing {
+			items = append(items, gaba.MetadataItem{Label: pak.StorefrontName, Value: pak.Version})
+		}
+		sections = append(sections, gaba.NewInfoSection(fmt.Sprintf("To Install (%d)", len(items)), items))
+	}
+
+	if len(diff.Installed) > 0 {
+		var items []gaba.MetadataItem
+		for _, pp := range diff.Installed {
+			value := pp.Version
+			if current := ips.AppState.InstalledPaks[pp.RepoURL].Version; current != pp.Version {
+				value = fmt.Sprintf("%s here, %s in profile", current, pp.Version)
+			}
+			items = append(items, gaba.MetadataItem{Label: pp.Name, Value: value})
+		}
+		sections = append(sections, gaba.NewInfoSection(fmt.Sprintf("Already Installed (%d)", len(items)), items))
+	}
+
+	if len(diff.Unavailable) > 0 {
+		var items []gaba.MetadataItem
+		for _, pp := range diff.Unavailable {
+			items = append(items, gaba.MetadataItem{Label: pp.Name, Value: pp.RepoURL})
+		}
+		sections = append(sections, gaba.NewInfoSection(fmt.Sprintf("Not in Storefront (%d)", len(items)), items))
+	}
+
+	if len(diff.Extra) > 0 {
+		var items []gaba.MetadataItem
+		for _, ip := range diff.Extra {
+			items = append(items, gaba.MetadataItem{Label: ip.DisplayName, Value: ip.Version})
+		}
+		sections = append(sections, gaba.NewInfoSection(fmt.Sprintf("Only on This Device (%d)", len(items)), items))
+	}
+
+	options := gaba.DefaultInfoScreenOptions()
+	options.Sections = sections
+	options.ShowThemeBackground = false
+
+	footerItems := []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+	}
+
+	if len(diff.Missing) > 0 {
+		footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "X", HelpText: fmt.Sprintf("Install %d", len(diff.Missing))})
+	} else if len(diff.Installed) > 0 {
+		footerItems = append(footerItems, gaba.FooterHelpItem{ButtonName: "X", HelpText: "Apply Settings"})
+	}
+
+	sel, err := gaba.DetailScreen("Import Profile", options, footerItems)
+	if err != nil {
+		logger.Error("Unable to display profile import", "error", err)
+		return models.Back(), err
+	}
+
+	if sel.IsNone() || len(diff.Missing)+len(diff.Installed) == 0 {
+		return models.Back(), nil
+	}
+
+	if len(diff.Missing) > 0 {
+		runBatchInstall("Profile Import", diff.Missing)
+	}
+
+	if err := p.ApplySettings(); err != nil {
+		logger.Error("Unable to apply profile settings", "error", err)
+		showProfileMessage("Unable to restore the profile's\npak settings!")
+	} else if len(diff.Missing) == 0 {
+		showProfileMessage("Profile settings applied!")
+	}
+
+	return models.Back().WithRefresh(), nil
+}
+
+// ExportProfile writes the installed paks and their settings to the SD card and tells the user
+// where to find the file.
+func ExportProfile() {
+	logger := common.GetLoggerInstance()
+
+	message := "Unable to export profile!"
+
+	path, err := profile.Export(state.PakStoreVersion)
+	if err != nil {
+		logger.Error("Unable to export profile", "error", err)
+	} else {
+		message = fmt.Sprintf("Profile saved to\n%s", path)
+	}
+
+	showProfileMessage(message)
+}
+
+func showProfileMessage(message string) {
+	gaba.ProcessMessage(message, gaba.ProcessMessageOptions{}, func() (interface{}, error) {
+		time.Sleep(settings.MessageDelay(3 * time.Second))
+		return nil, nil
+	})
+}
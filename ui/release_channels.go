@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	gaba "github.com/UncleJunVIP/gabagool/pkg/gabagool"
+	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	"github.com/UncleJunVIP/nextui-pak-store/database"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/state"
+	"qlova.tech/sum"
+)
+
+type ReleaseChannelsScreen struct {
+	AppState state.AppState
+	Position ListPosition
+}
+
+func InitReleaseChannelsScreen(appState state.AppState) ReleaseChannelsScreen {
+	return ReleaseChannelsScreen{
+		AppState: appState,
+	}
+}
+
+func (rcs ReleaseChannelsScreen) Name() sum.Int[models.ScreenName] {
+	return models.ScreenNames.ReleaseChannels
+}
+
+func (rcs ReleaseChannelsScreen) withAppState(appState state.AppState) models.Screen {
+	rcs.AppState = appState
+	return rcs
+}
+
+// Draw lists the installed paks with the channel they update from. Selecting a pak switches it
+// between stable and beta, the first entry switches every installed pak at once.
+func (rcs ReleaseChannelsScreen) Draw() (models.Navigation, error) {
+	logger := common.GetLoggerInstance()
+
+	var menuItems []gaba.MenuItem
+
+	allBeta := len(rcs.AppState.InstalledPaks) > 0
+
+	for _, installed := range rcs.AppState.InstalledPaks {
+		name := installed.DisplayName
+		for _, p := range rcs.AppState.Storefront.Paks {
+			if p.RepoURL == installed.RepoUrl.String {
+				name = p.StorefrontName
+			}
+		}
+
+		beta := installed.Beta == 1
+		allBeta = allBeta && beta
+
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:     channelLabel(name, beta),
+			Selected: false,
+			Focused:  false,
+			Metadata: installed.RepoUrl.String,
+		})
+	}
+
+	slices.SortFunc(menuItems, func(a, b gaba.MenuItem) int {
+		return strings.Compare(a.Text, b.Text)
+	})
+
+	menuItems = append([]gaba.MenuItem{{
+		Text:     channelLabel("All Paks", allBeta),
+		Selected: false,
+		Focused:  false,
+		Metadata: "",
+	}}, menuItems...)
+
+	options := gaba.DefaultListOptions("Release Channels", menuItems)
+	rcs.Position.apply(&options, len(menuItems))
+	options.EnableAction = true
+	options.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Switch"},
+	}
+
+	sel, err := gaba.List(options)
+	if err != nil {
+		return models.Back(), err
+	}
+
+	if sel.IsNone() || sel.Unwrap().SelectedIndex == -1 {
+		return models.Back(), nil
+	}
+
+	rcs.Position = positionOf(sel.Unwrap())
+
+	repoURL := sel.Unwrap().SelectedItem.Metadata.(string)
+
+	if repoURL == "" {
+		beta := int64(1)
+		if allBeta {
+			beta = 0
+		}
+
+		err = database.DBQ().SetBetaForAll(context.Background(), beta)
+	} else {
+		beta := int64(1)
+		if rcs.AppState.InstalledPaks[repoURL].Beta == 1 {
+			beta = 0
+		}
+
+		err = database.DBQ().SetBeta(context.Background(), database.SetBetaParams{
+			Beta:    beta,
+			RepoUrl: sql.NullString{String: repoURL, Valid: true},
+		})
+	}
+
+	if err != nil {
+		logger.Error("Unable to save release channel", "error", err, "repo", repoURL)
+		return models.Replace(rcs), err
+	}
+
+	return models.Replace(rcs).WithRefresh(), nil
+}
+
+func channelLabel(name string, beta bool) string {
+	if beta {
+		return name + ": Beta"
+	}
+	return name + ": Stable"
+}
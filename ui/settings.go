@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+
+	gaba "github.com/UncleJunVIP/gabagool/pkg/gabagool"
+	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/settings"
+	"qlova.tech/sum"
+)
+
+type SettingsScreen struct {
+	Position ListPosition
+}
+
+func InitSettingsScreen() SettingsScreen {
+	return SettingsScreen{}
+}
+
+func (ss SettingsScreen) Name() sum.Int[models.ScreenName] {
+	return models.ScreenNames.Settings
+}
+
+var storefrontSourceLabels = map[sum.Int[models.StorefrontSource]]string{
+	models.StorefrontSources.OfficialAndMirrors: "Official + Mirrors",
+	models.StorefrontSources.Official:           "Official Only",
+	models.StorefrontSources.Mirrors:            "Mirrors Only",
+}
+
+var offlineBehaviorLabels = map[sum.Int[models.OfflineBehavior]]string{
+	models.OfflineBehaviors.UseLastStorefront: "Use Last Storefront",
+	models.OfflineBehaviors.Quit:              "Quit",
+}
+
+var messageDurationLabels = map[sum.Int[models.MessageDuration]]string{
+	models.MessageDurations.Short:  "Short",
+	models.MessageDurations.Normal: "Normal",
+	models.MessageDurations.Long:   "Long",
+}
+
+// Draw lists every setting with its current value. Selecting a setting moves it to its next
+// value and saves it, then redraws the list in place. The last item exports the diagnostics and
+// history for a bug report.
+func (ss SettingsScreen) Draw() (models.Navigation, error) {
+	logger := common.GetLoggerInstance()
+
+	s := settings.Get()
+
+	channel := "Stable"
+	if s.BetaByDefault {
+		channel = "Beta"
+	}
+
+	items := []struct{ label, value string }{
+		{"Storefront Source", storefrontSourceLabels[s.StorefrontSource]},
+		{"Default Channel", channel},
+		{"Clean Up Temp Files", onOff(s.CleanupTempFiles)},
+		{"Confirm Uninstall", onOff(s.ConfirmUninstall)},
+		{"Parallel Downloads", concurrencyLabel(s.DownloadConcurrency)},
+		{"When Offline", offlineBehaviorLabels[s.OfflineBehavior]},
+		{"Message Duration", messageDurationLabels[s.MessageDuration]},
+	}
+
+	// The override can't be typed on the device, only cleared
+	if s.StorefrontOverride != "" {
+		items = append(items, struct{ label, value string }{"Storefront Override", overrideLabel(s.StorefrontOverride)})
+	}
+
+	var menuItems []gaba.MenuItem
+	for _, item := range items {
+		menuItems = append(menuItems, gaba.MenuItem{
+			Text:     item.label + ": " + item.value,
+			Selected: false,
+			Focused:  false,
+			Metadata: item.label,
+		})
+	}
+
+	menuItems = append(menuItems, gaba.MenuItem{
+		Text:     "Export Diagnostics",
+		Selected: false,
+		Focused:  false,
+		Metadata: "Export Diagnostics",
+	})
+
+	options := gaba.DefaultListOptions("Settings", menuItems)
+	ss.Position.apply(&options, len(menuItems))
+	options.EnableAction = true
+	options.FooterHelpItems = []gaba.FooterHelpItem{
+		{ButtonName: "B", HelpText: "Back"},
+		{ButtonName: "A", HelpText: "Change"},
+	}
+
+	sel, err := gaba.List(options)
+	if err != nil {
+		return models.Back(), err
+	}
+
+	if sel.IsNone() || sel.Unwrap().SelectedIndex == -1 {
+		return models.Back(), nil
+	}
+
+	ss.Position = positionOf(sel.Unwrap())
+
+	if sel.Unwrap().SelectedItem.Metadata.(string) == "Export Diagnostics" {
+		exportDiagnostics(nil)
+		return models.Replace(ss), nil
+	}
+
+	switch sel.Unwrap().SelectedItem.Metadata.(string) {
+	case "Storefront Source":
+		s.StorefrontSource = next([]sum.Int[models.StorefrontSource]{
+			models.StorefrontSources.OfficialAndMirrors,
+			models.StorefrontSources.Official,
+			models.StorefrontSources.Mirrors,
+		}, s.StorefrontSource)
+	case "Default Channel":
+		s.BetaByDefault = !s.BetaByDefault
+	case "Clean Up Temp Files":
+		s.CleanupTempFiles = !s.CleanupTempFiles
+	case "Confirm Uninstall":
+		s.ConfirmUninstall = !s.ConfirmUninstall
+	case "Parallel Downloads":
+		s.DownloadConcurrency = next(settings.DownloadConcurrencyOptions, s.DownloadConcurrency)
+	case "When Offline":
+		s.OfflineBehavior = next([]sum.Int[models.OfflineBehavior]{
+			models.OfflineBehaviors.UseLastStorefront,
+			models.OfflineBehaviors.Quit,
+		}, s.OfflineBehavior)
+	case "Message Duration":
+		s.MessageDuration = next([]sum.Int[models.MessageDuration]{
+			models.MessageDurations.Short,
+			models.MessageDurations.Normal,
+			models.MessageDurations.Long,
+		}, s.MessageDuration)
+	case "Storefront Override":
+		s.StorefrontOverride = ""
+	}
+
+	if err := settings.Save(s); err != nil {
+		logger.Error("Unable to save settings", "error", err)
+		return models.Replace(ss), err
+	}
+
+	return models.Replace(ss), nil
+}
+
+// next returns the value after current in values, wrapping around to the first.
+func next[T comparable](values []T, current T) T {
+	return values[(slices.Index(values, current)+1)%len(values)]
+}
+
+func onOff(b bool) string {
+	if b {
+		return "On"
+	}
+	return "Off"
+}
+
+// overrideLabel shows the override's host, which fits on screen where the full URL would not.
+func overrideLabel(override string) string {
+	u, err := url.Parse(override)
+	if err != nil || u.Host == "" {
+		return override
+	}
+	return u.Host
+}
+
+func concurrencyLabel(n int) string {
+	if n <= 0 {
+		return "Auto"
+	}
+	return strconv.Itoa(n)
+}
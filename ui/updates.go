@@ -12,6 +12,7 @@ import (
 
 type UpdatesScreen struct {
 	AppState state.AppState
+	Position ListPosition
 }
 
 func InitUpdatesScreen(appState state.AppState) UpdatesScreen {
@@ -24,16 +25,21 @@ func (us UpdatesScreen) Name() sum.Int[models.ScreenName] {
 	return models.ScreenNames.Updates
 }
 
-func (us UpdatesScreen) Draw() (selection interface{}, exitCode int, e error) {
+func (us UpdatesScreen) withAppState(appState state.AppState) models.Screen {
+	us.AppState = appState
+	return us
+}
+
+func (us UpdatesScreen) Draw() (models.Navigation, error) {
 	if len(us.AppState.UpdatesAvailable) == 0 {
-		return nil, 2, nil
+		return models.Back(), nil
 	}
 
 	var menuItems []gabagool.MenuItem
 
 	for _, pak := range us.AppState.UpdatesAvailable {
 		menuItems = append(menuItems, gabagool.MenuItem{
-			Text:     pak.StorefrontName,
+			Text:     betaLabel(pak),
 			Selected: false,
 			Focused:  false,
 			Metadata: []models.Pak{pak},
@@ -54,6 +60,7 @@ func (us UpdatesScreen) Draw() (selection interface{}, exitCode int, e error) {
 	}
 
 	options := gabagool.DefaultListOptions("Available Pak Updates", menuItems)
+	us.Position.apply(&options, len(menuItems))
 	options.EnableAction = true
 	options.FooterHelpItems = []gabagool.FooterHelpItem{
 		{ButtonName: "B", HelpText: "Back"},
@@ -62,12 +69,23 @@ func (us UpdatesScreen) Draw() (selection interface{}, exitCode int, e error) {
 
 	sel, err := gabagool.List(options)
 	if err != nil {
-		return nil, -1, err
+		return models.Back(), err
 	}
 
 	if sel.IsNone() || sel.Unwrap().SelectedIndex == -1 {
-		return nil, 2, nil
+		return models.Back(), nil
 	}
 
-	return sel.Unwrap().SelectedItem.Metadata.([]models.Pak), 0, nil
+	us.Position = positionOf(sel.Unwrap())
+
+	paks := sel.Unwrap().SelectedItem.Metadata.([]models.Pak)
+
+	return models.Push(us, InitPakInfoScreen(paks, true, false, us.AppState)), nil
+}
+
+func betaLabel(pak models.Pak) string {
+	if pak.IsBeta {
+		return pak.StorefrontName + " [Beta " + pak.Version + "]"
+	}
+	return pak.StorefrontName
 }
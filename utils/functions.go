@@ -3,24 +3,36 @@ package utils
 import (
 	"archive/zip"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"image/color"
 	"io"
-	"net"
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/UncleJunVIP/gabagool/pkg/gabagool"
 	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	"github.com/UncleJunVIP/nextui-pak-store/forge"
+	"github.com/UncleJunVIP/nextui-pak-store/httpclient"
 	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/schema"
 	"github.com/skip2/go-qrcode"
 )
 
+// tempFilePrefix marks every temp file Pak Store creates, so leftovers can be cleaned up safely.
+const tempFilePrefix = "pak-store-"
+
+// DefaultParallelDownloads is how many pak archives are downloaded at once unless the user picks
+// a different number.
+const DefaultParallelDownloads = 3
+
 func GetSDRoot() string {
 	if os.Getenv("ENVIRONMENT") == "DEV" {
 		return os.Getenv("SD_ROOT")
@@ -45,30 +57,37 @@ func GetEmulatorRoot() string {
 	return models.EmulatorRoot
 }
 
-func FetchStorefront() (models.Storefront, error) {
-	logger := common.GetLoggerInstance()
+// LoadLocalStorefront reads the storefront.json written by the builder, for development.
+func LoadLocalStorefront() (models.Storefront, error) {
+	data, err := os.ReadFile(models.StorefrontJsonFilename)
+	if err != nil {
+		return models.Storefront{}, fmt.Errorf("failed to read local storefront.json: %w", err)
+	}
 
-	var data []byte
-	var err error
+	var sf models.Storefront
+	if err := json.Unmarshal(data, &sf); err != nil {
+		return models.Storefront{}, err
+	}
 
-	if override := os.Getenv("STOREFRONT_OVERRIDE"); override != "" {
-		data, err = fetch(override)
-		if err != nil {
-			return models.Storefront{}, err
-		}
-	} else if os.Getenv("ENVIRONMENT") == "DEV" {
-		data, err = os.ReadFile("storefront.json")
-		if err != nil {
-			return models.Storefront{}, fmt.Errorf("failed to read local storefront.json: %w", err)
-		}
-	} else {
-		data, err = fetch(models.StorefrontJsonURL)
-		if err != nil {
-			data, err = fetch(models.StorefrontJsonBackupURL)
-			if err != nil {
-				return models.Storefront{}, err
-			}
+	return prepareStorefront(sf, models.StorefrontJsonFilename), nil
+}
+
+// FetchStorefrontFrom downloads the storefront published at storefrontURL. When the URL points at
+// a storefront.json the builder also published the delta index next to it, which is preferred.
+func FetchStorefrontFrom(storefrontURL string) (models.Storefront, error) {
+	logger := common.GetLoggerInstance()
+
+	if path.Base(storefrontURL) == models.StorefrontJsonFilename {
+		sf, err := fetchDeltaStorefront(storefrontURL)
+		if err == nil {
+			return prepareStorefront(sf, storefrontURL), nil
 		}
+		logger.Warn("Unable to fetch storefront index, falling back to the full storefront", "error", err, "url", storefrontURL)
+	}
+
+	data, err := fetchConditional(storefrontURL)
+	if err != nil {
+		return models.Storefront{}, err
 	}
 
 	var sf models.Storefront
@@ -76,18 +95,25 @@ func FetchStorefront() (models.Storefront, error) {
 		return models.Storefront{}, err
 	}
 
+	return prepareStorefront(sf, storefrontURL), nil
+}
+
+func prepareStorefront(sf models.Storefront, source string) models.Storefront {
 	for i, p := range sf.Paks {
 		if filepath.Ext(p.ReleaseFilename) == ".pakz" {
 			sf.Paks[i].IsPakZ = true
 		}
+		sf.Paks[i].SourceStorefront = source
 	}
 
-	logger.Info("Fetched storefront", "name", sf.Name)
-
-	return sf, nil
+	return sf
 }
 
+// ParseJSONFile reads a pak.json. Unknown or mistyped fields are logged rather than rejected, so a
+// stray key cannot stop the Pak Store from starting. The storefront builder is the strict check.
 func ParseJSONFile(filePath string, out *models.Pak) error {
+	logger := common.GetLoggerInstance()
+
 	file, err := os.Open(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
@@ -99,6 +125,10 @@ func ParseJSONFile(filePath string, out *models.Pak) error {
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
+	for _, problem := range schema.Check(data, models.PakJson{}) {
+		logger.Warn("Problem in pak.json", "error", problem, "path", filePath)
+	}
+
 	if err := json.Unmarshal(data, out); err != nil {
 		return fmt.Errorf("failed to unmarshal JSON: %w", err)
 	}
@@ -106,12 +136,27 @@ func ParseJSONFile(filePath string, out *models.Pak) error {
 	return nil
 }
 
+func PakDownloadURL(pak models.Pak) (string, error) {
+	if pak.DownloadURL != "" {
+		return pak.DownloadURL, nil
+	}
+
+	provider, err := forge.ForRepo(pak.RepoURL, pak.Forge)
+	if err != nil {
+		return "", err
+	}
+
+	return provider.ReleaseAssetURL(pak.Version, pak.ReleaseFilename), nil
+}
+
 func DownloadPakArchive(pak models.Pak) (tempFile string, completed bool, error error) {
 	logger := common.GetLoggerInstance()
 
-	releasesStub := fmt.Sprintf("/releases/download/%s/", pak.Version)
-	dl := pak.RepoURL + releasesStub + pak.ReleaseFilename
-	tmp := filepath.Join("/tmp", pak.ReleaseFilename)
+	dl, err := PakDownloadURL(pak)
+	if err != nil {
+		return "", false, err
+	}
+	tmp := filepath.Join("/tmp", tempFilePrefix+pak.ReleaseFilename)
 
 	message := fmt.Sprintf("Downloading %s %s...", pak.StorefrontName, pak.Version)
 
@@ -119,7 +164,7 @@ func DownloadPakArchive(pak models.Pak) (tempFile string, completed bool, error
 		URL:         dl,
 		Location:    tmp,
 		DisplayName: message,
-	}}, make(map[string]string), true)
+	}}, map[string]string{"User-Agent": httpclient.UserAgent()}, true)
 
 	if err == nil && len(res.Errors) > 0 {
 		err = res.Errors[0]
@@ -132,9 +177,140 @@ func DownloadPakArchive(pak models.Pak) (tempFile string, completed bool, error
 		return "", false, nil
 	}
 
+	if err := VerifyPakArchive(pak, tmp); err != nil {
+		logger.Error("Downloaded archive failed verification", "error", err, "pak", pak.StorefrontName)
+		return "", false, err
+	}
+
 	return tmp, true, nil
 }
 
+type ArchiveDownload struct {
+	Pak      models.Pak
+	TempFile string
+	Err      error
+}
+
+// DownloadPakArchives downloads several pak archives behind one progress message, at most
+// concurrency at a time, or DefaultParallelDownloads when concurrency is not positive.
+// Each result carries its own error, so one failed or slow download doesn't hold up the others.
+func DownloadPakArchives(paks []models.Pak, concurrency int) []ArchiveDownload {
+	logger := common.GetLoggerInstance()
+
+	downloads := make([]ArchiveDownload, len(paks))
+	for i, pak := range paks {
+		downloads[i] = ArchiveDownload{
+			Pak:      pak,
+			TempFile: filepath.Join("/tmp", fmt.Sprintf("%s%d-%s", tempFilePrefix, i, pak.ReleaseFilename)),
+		}
+	}
+
+	if concurrency <= 0 {
+		concurrency = DefaultParallelDownloads
+	}
+
+	message := fmt.Sprintf("Downloading %d paks...", len(paks))
+	if len(paks) == 1 {
+		message = fmt.Sprintf("Downloading %s %s...", paks[0].StorefrontName, paks[0].Version)
+	}
+
+	gabagool.ProcessMessage(message, gabagool.ProcessMessageOptions{}, func() (interface{}, error) {
+		workers := make(chan struct{}, concurrency)
+
+		var wg sync.WaitGroup
+		for i := range downloads {
+			wg.Add(1)
+			go func(d *ArchiveDownload) {
+				defer wg.Done()
+
+				workers <- struct{}{}
+				defer func() { <-workers }()
+
+				d.Err = downloadPakArchive(d.Pak, d.TempFile)
+				if d.Err != nil {
+					logger.Error("Error downloading", "error", d.Err, "pak", d.Pak.StorefrontName)
+				}
+			}(&downloads[i])
+		}
+		wg.Wait()
+
+		return nil, nil
+	})
+
+	return downloads
+}
+
+func downloadPakArchive(pak models.Pak, path string) error {
+	dl, err := PakDownloadURL(pak)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpclient.Default().Get(dl)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &httpclient.StatusError{URL: dl, StatusCode: resp.StatusCode}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	written, err := io.Copy(f, resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+
+	if resp.ContentLength > 0 && written != resp.ContentLength {
+		return fmt.Errorf("download was incomplete")
+	}
+
+	return VerifyPakArchive(pak, path)
+}
+
+// VerifyPakArchive checks a downloaded archive against the size and checksum the storefront
+// published. Forges that publish neither still get a check that the archive is a complete zip.
+func VerifyPakArchive(pak models.Pak, path string) error {
+	info, err := os.Stat(path)
+	switch {
+	case err != nil:
+		return fmt.Errorf("download failed")
+	case info.Size() == 0:
+		return fmt.Errorf("download was empty")
+	case pak.ReleaseSize > 0 && info.Size() != pak.ReleaseSize:
+		return fmt.Errorf("download was incomplete")
+	}
+
+	if pak.ReleaseSHA256 != "" {
+		sha, err := FileSHA256(path)
+		if err != nil {
+			return err
+		}
+
+		if !strings.EqualFold(sha, pak.ReleaseSHA256) {
+			return fmt.Errorf("archive checksum does not match the storefront")
+		}
+
+		return nil
+	}
+
+	// A zip's directory is at the end of the file, so a cut off download cannot be opened
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return fmt.Errorf("download is not a complete archive")
+	}
+
+	return r.Close()
+}
+
 func RunScript(script models.Script, scriptName string) error {
 	logger := common.GetLoggerInstance()
 
@@ -185,65 +361,172 @@ func RunScript(script models.Script, scriptName string) error {
 	return err
 }
 
-func UnzipPakArchive(pak models.Pak, tmp string) error {
-	logger := common.GetLoggerInstance()
-
-	pakDestination := ""
-
+// PakDestination is the directory a pak archive is extracted into.
+func PakDestination(pak models.Pak) string {
 	if pak.IsPakZ {
-		pakDestination = GetSDRoot()
+		return GetSDRoot()
 	} else if pak.PakType == models.PakTypes.TOOL {
-		pakDestination = filepath.Join(GetToolRoot(), pak.Name+".pak")
+		return filepath.Join(GetToolRoot(), pak.Name+".pak")
 	} else if pak.PakType == models.PakTypes.EMU {
-		pakDestination = filepath.Join(GetEmulatorRoot(), pak.Name+".pak")
+		return filepath.Join(GetEmulatorRoot(), pak.Name+".pak")
 	}
 
-	_, err := gabagool.ProcessMessage(fmt.Sprintf("%s %s...", "Unzipping", pak.StorefrontName), gabagool.ProcessMessageOptions{}, func() (interface{}, error) {
-		err := Unzip(tmp, pakDestination, pak, false)
-		if err != nil {
-			return nil, err
+	return ""
+}
+
+// InstallPaths lists what an archive puts on the SD card. A pak lives in its own directory. A PakZ
+// shares directories like Roms with everything else, so every file it writes is listed instead.
+func InstallPaths(pak models.Pak, tmp string) ([]string, error) {
+	dest := PakDestination(pak)
+	if !pak.IsPakZ {
+		return []string{dest}, nil
+	}
+
+	r, err := zip.OpenReader(tmp)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var paths []string
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() {
+			continue
 		}
 
-		time.Sleep(1 * time.Second)
+		p := filepath.Join(dest, f.Name)
+		if !strings.HasPrefix(p, filepath.Clean(dest)+string(os.PathSeparator)) {
+			return nil, fmt.Errorf("illegal file path: %s", p)
+		}
 
-		return nil, nil
-	})
+		paths = append(paths, p)
+	}
+
+	return paths, nil
+}
 
+// FileSHA256 returns the hex encoded SHA-256 of a file.
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
 	if err != nil {
-		gabagool.ProcessMessage(fmt.Sprintf("Unable to unzip %s", pak.StorefrontName), gabagool.ProcessMessageOptions{}, func() (interface{}, error) {
-			time.Sleep(3 * time.Second)
-			return nil, nil
-		})
-		logger.Error("Unable to unzip pak", "error", err)
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// RemoveTempFiles deletes downloaded archives and QR codes left behind by earlier sessions.
+func RemoveTempFiles() {
+	logger := common.GetLoggerInstance()
+
+	for _, dir := range []string{"/tmp", os.TempDir()} {
+		matches, _ := filepath.Glob(filepath.Join(dir, tempFilePrefix+"*"))
+		for _, m := range matches {
+			if err := os.RemoveAll(m); err != nil {
+				logger.Warn("Unable to remove temp file", "error", err, "file", m)
+			}
+		}
+	}
+}
+
+// ExtractPakArchive unpacks a downloaded archive into the pak's install location.
+func ExtractPakArchive(pak models.Pak, tmp string) error {
+	return Unzip(tmp, PakDestination(pak), pak, false)
+}
+
+// StagePakArchive unpacks a downloaded archive next to the pak's install location, on top of a
+// copy of the installed files, and returns the staging directory. Files the user added to the pak
+// survive an update just as they would with ExtractPakArchive.
+func StagePakArchive(pak models.Pak, tmp string) (string, error) {
+	dest := PakDestination(pak)
+	staged := dest + ".staging"
+
+	if err := os.RemoveAll(staged); err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(dest); err == nil {
+		if err := os.CopyFS(staged, os.DirFS(dest)); err != nil {
+			_ = os.RemoveAll(staged)
+			return "", fmt.Errorf("unable to copy installed files: %w", err)
+		}
+	}
+
+	if err := Unzip(tmp, staged, pak, false); err != nil {
+		_ = os.RemoveAll(staged)
+		return "", err
+	}
+
+	return staged, nil
+}
+
+// SwapDirectory moves staged into place at dest. Whatever was at dest is moved aside and its new
+// path returned, empty if there was nothing, so the caller can restore it or remove it.
+func SwapDirectory(dest string, staged string) (previous string, err error) {
+	if _, err := os.Stat(dest); err == nil {
+		previous = dest + ".previous"
+
+		if err := os.RemoveAll(previous); err != nil {
+			return "", err
+		}
+
+		if err := os.Rename(dest, previous); err != nil {
+			return "", err
+		}
+	}
+
+	if err := os.Rename(staged, dest); err != nil {
+		if previous != "" {
+			_ = os.Rename(previous, dest)
+		}
+		return "", err
+	}
+
+	return previous, nil
+}
+
+// RestoreDirectory undoes SwapDirectory, putting previous back at dest.
+func RestoreDirectory(dest string, previous string) error {
+	if err := os.RemoveAll(dest); err != nil {
 		return err
 	}
 
-	return nil
+	if previous == "" {
+		return nil
+	}
+
+	return os.Rename(previous, dest)
 }
 
 func fetch(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+	resp, err := httpclient.Default().Get(url)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP request failed with status code: %d", resp.StatusCode)
+		return nil, &httpclient.StatusError{URL: url, StatusCode: resp.StatusCode}
 	}
 
 	return io.ReadAll(resp.Body)
 }
 
 func DownloadTempFile(url string) (string, error) {
-	resp, err := http.Get(url)
+	resp, err := httpclient.Default().Get(url)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("bad status: %s", resp.Status)
+		return "", &httpclient.StatusError{URL: url, StatusCode: resp.StatusCode}
 	} else if resp.ContentLength <= 0 {
 		return "", fmt.Errorf("empty response")
 	}
@@ -273,7 +556,7 @@ func CreateTempQRCode(content string, size int) (string, error) {
 	qr.ForegroundColor = color.White
 	qr.DisableBorder = true
 
-	tempFile, err := os.CreateTemp("", "qrcode-*")
+	tempFile, err := os.CreateTemp("", tempFilePrefix+"qrcode-*")
 
 	err = qr.Write(size, tempFile)
 
@@ -393,8 +676,40 @@ func ShouldIgnoreFile(filePath string, pak models.Pak) bool {
 	return false
 }
 
-func IsConnectedToInternet() bool {
-	timeout := 5 * time.Second
-	_, err := net.DialTimeout("tcp", "8.8.8.8:53", timeout)
-	return err == nil
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// DirSize adds up the size of every file under dir.
+func DirSize(dir string) (int64, error) {
+	var size int64
+
+	err := filepath.WalkDir(dir, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size, err
 }
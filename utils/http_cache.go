@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	"github.com/UncleJunVIP/nextui-pak-store/httpclient"
+	"github.com/UncleJunVIP/nextui-pak-store/storefront"
+)
+
+type cacheValidators struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func httpCachePaths(url string) (body string, meta string) {
+	dir := filepath.Join(GetCacheRoot(), "http")
+	key := storefront.Hash([]byte(url))
+	return filepath.Join(dir, key+".body"), filepath.Join(dir, key+".json")
+}
+
+// fetchConditional revalidates a previously downloaded copy of url with its ETag and
+// Last-Modified validators and reuses the cached copy when the server answers 304.
+func fetchConditional(url string) ([]byte, error) {
+	logger := common.GetLoggerInstance()
+
+	bodyPath, metaPath := httpCachePaths(url)
+
+	var validators cacheValidators
+	cached, err := os.ReadFile(bodyPath)
+	if err == nil {
+		if meta, err := os.ReadFile(metaPath); err == nil {
+			_ = json.Unmarshal(meta, &validators)
+		}
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached != nil && validators.URL == url {
+		if validators.ETag != "" {
+			req.Header.Set("If-None-Match", validators.ETag)
+		}
+		if validators.LastModified != "" {
+			req.Header.Set("If-Modified-Since", validators.LastModified)
+		}
+	}
+
+	resp, err := httpclient.Default().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		logger.Info("Using cached copy", "url", url)
+		return cached, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &httpclient.StatusError{URL: url, StatusCode: resp.StatusCode}
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	validators = cacheValidators{
+		URL:          url,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+
+	if validators.ETag != "" || validators.LastModified != "" {
+		if err := storeCachedResponse(bodyPath, metaPath, data, validators); err != nil {
+			logger.Warn("Unable to cache response", "error", err, "url", url)
+		}
+	}
+
+	return data, nil
+}
+
+func storeCachedResponse(bodyPath string, metaPath string, data []byte, validators cacheValidators) error {
+	if err := os.MkdirAll(filepath.Dir(bodyPath), 0755); err != nil {
+		return err
+	}
+
+	meta, err := json.Marshal(validators)
+	if err != nil {
+		return err
+	}
+
+	// Write the body first so validators never describe a body that isn't on disk
+	if err := os.WriteFile(bodyPath, data, 0644); err != nil {
+		return err
+	}
+
+	return os.WriteFile(metaPath, meta, 0644)
+}
@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+)
+
+// MovedFiles are files moved out of the way while a PakZ is installed or uninstalled, so the
+// change can be undone if the database cannot record it.
+type MovedFiles struct {
+	dir   string   // Where the files were moved to
+	paths []string // Where the files came from
+}
+
+func pakZBackupDir(pak models.Pak) string {
+	return filepath.Join(GetSDRoot(), ".pak-store-backup", pak.Name)
+}
+
+// MoveFilesAside moves the existing files among paths to a backup directory on the SD card.
+// Paths that do not exist are skipped. If a file cannot be moved, the ones already moved are
+// put back.
+func MoveFilesAside(pak models.Pak, paths []string) (MovedFiles, error) {
+	m := MovedFiles{dir: pakZBackupDir(pak)}
+
+	if err := os.RemoveAll(m.dir); err != nil {
+		return m, err
+	}
+
+	for _, p := range paths {
+		info, err := os.Lstat(p)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return m, errors.Join(err, m.Restore())
+		}
+
+		if info.IsDir() {
+			return m, errors.Join(fmt.Errorf("%s is a directory", p), m.Restore())
+		}
+
+		backup, err := m.backupPath(p)
+		if err == nil {
+			err = os.MkdirAll(filepath.Dir(backup), 0755)
+		}
+		if err == nil {
+			err = os.Rename(p, backup)
+		}
+		if err != nil {
+			return m, errors.Join(err, m.Restore())
+		}
+
+		m.paths = append(m.paths, p)
+	}
+
+	return m, nil
+}
+
+func (m MovedFiles) backupPath(p string) (string, error) {
+	rel, err := filepath.Rel(GetSDRoot(), p)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(m.dir, rel), nil
+}
+
+// Restore moves the files back where they came from, replacing whatever is there now.
+func (m MovedFiles) Restore() error {
+	var errs []error
+
+	for _, p := range m.paths {
+		backup, err := m.backupPath(p)
+		if err == nil {
+			err = os.RemoveAll(p)
+		}
+		if err == nil {
+			err = os.Rename(backup, p)
+		}
+		errs = append(errs, err)
+	}
+
+	errs = append(errs, m.Discard())
+
+	return errors.Join(errs...)
+}
+
+// Discard deletes the moved files for good.
+func (m MovedFiles) Discard() error {
+	if err := os.RemoveAll(m.dir); err != nil {
+		return err
+	}
+
+	// Only succeeds once no other pak has files set aside
+	_ = os.Remove(filepath.Dir(m.dir))
+
+	return nil
+}
+
+// RemoveFiles deletes the given files, then any directories they leave empty, up to the SD card root.
+func RemoveFiles(paths []string) error {
+	var errs []error
+
+	for _, p := range paths {
+		if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+
+	root := filepath.Clean(GetSDRoot())
+	for _, p := range paths {
+		for dir := filepath.Dir(p); dir != root && len(dir) > len(root); dir = filepath.Dir(dir) {
+			// Fails, and stops, at the first directory that still has something in it
+			if os.Remove(dir) != nil {
+				break
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/UncleJunVIP/nextui-pak-shared-functions/common"
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+	"github.com/UncleJunVIP/nextui-pak-store/storefront"
+)
+
+const maxConcurrentDetailDownloads = 4
+
+func GetCacheRoot() string {
+	if os.Getenv("ENVIRONMENT") == "DEV" {
+		return "cache"
+	}
+
+	return filepath.Join(models.PakStoreConfigRoot, "cache")
+}
+
+// siblingURL swaps the file name of a storefront URL, so the index and pak details
+// are always fetched from the same host as the storefront they belong to.
+func siblingURL(storefrontURL string, name string) string {
+	return storefrontURL[:strings.LastIndex(storefrontURL, "/")+1] + name
+}
+
+// fetchDeltaStorefront assembles the storefront from its compact index, reusing cached
+// pak detail documents whose hash is unchanged and downloading only the rest.
+func fetchDeltaStorefront(storefrontURL string) (models.Storefront, error) {
+	logger := common.GetLoggerInstance()
+
+	data, err := fetchConditional(siblingURL(storefrontURL, models.StorefrontIndexFilename))
+	if err != nil {
+		return models.Storefront{}, err
+	}
+
+	var index models.StorefrontIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return models.Storefront{}, fmt.Errorf("invalid storefront index: %w", err)
+	}
+
+	cacheDir := filepath.Join(GetCacheRoot(), models.PakDetailsDir)
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return models.Storefront{}, err
+	}
+
+	paks := make([]models.Pak, len(index.Paks))
+	errs := make([]error, len(index.Paks))
+	downloaded := 0
+
+	sem := make(chan struct{}, maxConcurrentDetailDownloads)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for i, entry := range index.Paks {
+		cached := filepath.Join(cacheDir, entry.Hash+".json")
+
+		if detail, err := os.ReadFile(cached); err == nil && storefront.Hash(detail) == entry.Hash {
+			errs[i] = json.Unmarshal(detail, &paks[i])
+			continue
+		}
+
+		wg.Add(1)
+		go func(index int, entry models.StorefrontIndexEntry, cached string) {
+			sem <- struct{}{}
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			detail, err := fetch(siblingURL(storefrontURL, models.PakDetailsDir+"/"+entry.Hash+".json"))
+			if err != nil {
+				errs[index] = err
+				return
+			}
+
+			if storefront.Hash(detail) != entry.Hash {
+				errs[index] = fmt.Errorf("pak details for %s do not match the index", entry.RepoURL)
+				return
+			}
+
+			if err := json.Unmarshal(detail, &paks[index]); err != nil {
+				errs[index] = err
+				return
+			}
+
+			if err := os.WriteFile(cached, detail, 0644); err != nil {
+				logger.Warn("Unable to cache pak details", "error", err, "repo", entry.RepoURL)
+			}
+
+			mu.Lock()
+			downloaded++
+			mu.Unlock()
+		}(i, entry, cached)
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return models.Storefront{}, err
+		}
+	}
+
+	pruneDetailCache(cacheDir, index)
+
+	logger.Info("Assembled storefront from index",
+		"paks", len(index.Paks),
+		"downloaded", downloaded,
+		"cached", len(index.Paks)-downloaded)
+
+	return models.Storefront{
+		Name:        index.Name,
+		URL:         index.URL,
+		GeneratedAt: index.GeneratedAt,
+		Paks:        paks,
+	}, nil
+}
+
+// pruneDetailCache removes cached pak details the index no longer refers to.
+func pruneDetailCache(cacheDir string, index models.StorefrontIndex) {
+	logger := common.GetLoggerInstance()
+
+	current := make(map[string]bool)
+	for _, entry := range index.Paks {
+		current[entry.Hash+".json"] = true
+	}
+
+	entries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return
+	}
+
+	for _, e := range entries {
+		if !current[e.Name()] {
+			if err := os.Remove(filepath.Join(cacheDir, e.Name())); err != nil {
+				logger.Warn("Unable to remove stale pak details", "error", err, "file", e.Name())
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/UncleJunVIP/nextui-pak-store/models"
+)
+
+func storefrontSnapshotPath() string {
+	return filepath.Join(GetCacheRoot(), models.StorefrontJsonFilename)
+}
+
+// SaveStorefrontSnapshot keeps a copy of the last storefront that loaded, to fall back on offline.
+func SaveStorefrontSnapshot(sf models.Storefront) error {
+	data, err := json.Marshal(sf)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(GetCacheRoot(), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(storefrontSnapshotPath(), data, 0644)
+}
+
+// LoadStorefrontSnapshot reads the copy saved by SaveStorefrontSnapshot. Paks are attributed to the
+// storefront's own URL, as the mirror it was originally loaded from is not kept.
+func LoadStorefrontSnapshot() (models.Storefront, error) {
+	data, err := os.ReadFile(storefrontSnapshotPath())
+	if err != nil {
+		return models.Storefront{}, err
+	}
+
+	var sf models.Storefront
+	if err := json.Unmarshal(data, &sf); err != nil {
+		return models.Storefront{}, err
+	}
+
+	return prepareStorefront(sf, sf.URL), nil
+}
@@ -0,0 +1,166 @@
+// Package version orders the version strings pak authors actually use. Besides semver it
+// understands calendar versions (2024.05.01, 2024-05-01, 2024-05), numbers with any count of
+// parts (1.2.3.4) and build numbers with a known prefix (v1.2, r45, build-12, release-3).
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// Only these prefixes are dropped, so words like beta1 or latest aren't read as versions
+	prefixPattern = regexp.MustCompile(`(?i)^(?:version|release|build|v|r)[-_.]?(\d)`)
+	datePattern   = regexp.MustCompile(`^\d{4}-\d{1,2}(?:-\d{1,2})?$`)
+)
+
+type Version struct {
+	Original   string
+	Parts      []int
+	Prerelease []string
+}
+
+// Parse reads a version. Build metadata after a "+" is ignored, as in semver.
+func Parse(s string) (Version, error) {
+	v := Version{Original: s}
+
+	s = strings.TrimSpace(s)
+	s, _, _ = strings.Cut(s, "+")
+	s = prefixPattern.ReplaceAllString(s, "$1")
+
+	if datePattern.MatchString(s) {
+		s = strings.ReplaceAll(s, "-", ".")
+	}
+
+	main, pre, hasPre := strings.Cut(s, "-")
+	if main == "" {
+		return v, fmt.Errorf("%q has no version number", v.Original)
+	}
+
+	for _, part := range strings.Split(main, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("%q has a non-numeric part %q", v.Original, part)
+		}
+		v.Parts = append(v.Parts, n)
+	}
+
+	if hasPre {
+		if pre == "" {
+			return v, fmt.Errorf("%q has an empty prerelease", v.Original)
+		}
+
+		for _, id := range strings.Split(pre, ".") {
+			if id == "" {
+				return v, fmt.Errorf("%q has an empty prerelease identifier", v.Original)
+			}
+			v.Prerelease = append(v.Prerelease, id)
+		}
+	}
+
+	return v, nil
+}
+
+func IsValid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// IsPrerelease reports whether s is a valid version with a prerelease suffix, e.g. 1.2.0-beta.1.
+func IsPrerelease(s string) bool {
+	v, err := Parse(s)
+	return err == nil && len(v.Prerelease) > 0
+}
+
+// Compare returns -1, 0 or 1 when a is older than, the same as or newer than b.
+// Missing parts count as zero, so 1.2 and 1.2.0 are equal. Versions that cannot be parsed
+// sort before every valid version and compare to each other as plain strings.
+func Compare(a string, b string) int {
+	va, errA := Parse(a)
+	vb, errB := Parse(b)
+
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+
+	return va.Compare(vb)
+}
+
+func (v Version) Compare(o Version) int {
+	for i := 0; i < max(len(v.Parts), len(o.Parts)); i++ {
+		if c := compareInt(part(v.Parts, i), part(o.Parts, i)); c != 0 {
+			return c
+		}
+	}
+
+	// A release is newer than any of its prereleases
+	switch {
+	case len(v.Prerelease) == 0 && len(o.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(o.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < min(len(v.Prerelease), len(o.Prerelease)); i++ {
+		if c := compareIdentifier(v.Prerelease[i], o.Prerelease[i]); c != 0 {
+			return c
+		}
+	}
+
+	return compareInt(len(v.Prerelease), len(o.Prerelease))
+}
+
+// IsUpdate reports whether available should be offered over installed. When either version
+// cannot be ordered any difference counts as an update, so a pak is never stuck on an old
+// release and never offered the version it already has.
+func IsUpdate(installed string, available string) bool {
+	if !IsValid(installed) || !IsValid(available) {
+		return strings.TrimSpace(installed) != strings.TrimSpace(available)
+	}
+
+	return Compare(installed, available) < 0
+}
+
+func part(parts []int, i int) int {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return 0
+}
+
+func compareInt(a int, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareIdentifier follows semver: numeric identifiers compare numerically and sort
+// before alphanumeric ones, which compare as strings.
+func compareIdentifier(a string, b string) int {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+
+	switch {
+	case errA == nil && errB == nil:
+		return compareInt(na, nb)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,107 @@
+package version
+
+import "testing"
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.10.0", "1.9.0", 1},
+		{"1.9.0", "1.10.0", -1},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.0", "1.0.0", 0},
+		{"1", "1.0.0.0", 0},
+		{"2024.05.01", "2024.04.30", 1},
+		{"2024.5.1", "2024.05.01", 0},
+		{"2024-05-01", "2024-04-30", 1},
+		{"2024-05-01", "2024.05.01", 0},
+		{"2024-12-01", "2024-9-15", 1},
+		{"2024-05", "2024-04", 1},
+		{"2024-05", "2024.05", 0},
+		{"2024-05", "2024-04-30", 1},
+		{"1.2.3.4", "1.2.3.3", 1},
+		{"1.2.3.10", "1.2.3.9", 1},
+		{"1.2.3.4", "1.2.3", 1},
+		{"r45", "r9", 1},
+		{"r9", "r45", -1},
+		{"build-12", "build-12", 0},
+		{"release-3", "release-2", 1},
+		{"V1.2.0", "version1.2", 0},
+		{"beta1", "0.9", -1},
+		{"0.9", "beta1", 1},
+		{"1.0.0-beta", "1.0.0", -1},
+		{"1.0.0", "1.0.0-rc.1", 1},
+		{"1.0.0-alpha", "1.0.0-beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.10", -1},
+		{"1.0.0-beta", "1.0.0-beta.1", -1},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"1.0.0+build.5", "1.0.0", 0},
+		{"2.0.1a", "1.0.0", -1},
+		{"1.0.0", "2.0.1a", 1},
+	}
+
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestIsUpdate(t *testing.T) {
+	tests := []struct {
+		installed, available string
+		want                 bool
+	}{
+		{"1.9.0", "1.10.0", true},
+		{"1.10.0", "1.9.0", false},
+		{"1.0", "1.0.0", false},
+		{"2024.04.30", "2024.05.01", true},
+		{"2024-05-01", "2024-05-01", false},
+		{"2024-04", "2024-05", true},
+		{"2024-05", "2024-05", false},
+		{"1.2.3.4", "1.2.3.5", true},
+		{"r9", "r45", true},
+		{"1.0.0-beta.1", "1.0.0", true},
+		{"1.0.0", "1.0.0-beta.1", false},
+		{"2.0.1a", "2.0.1b", true},
+		{"2.0.1a", " 2.0.1a ", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsUpdate(tt.installed, tt.available); got != tt.want {
+			t.Errorf("IsUpdate(%q, %q) = %t, want %t", tt.installed, tt.available, got, tt.want)
+		}
+	}
+}
+
+func TestIsPrerelease(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"1.2.0-beta.1", true},
+		{"v1.2.0-rc1", true},
+		{"1.2.0", false},
+		{"2024-05-01", false},
+		{"2024-05", false},
+		{"r45", false},
+		{"1.2.0+build.3", false},
+		{"1.2.0-", false},
+		{"2.0.1a", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsPrerelease(tt.version); got != tt.want {
+			t.Errorf("IsPrerelease(%q) = %t, want %t", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseRejectsInvalid(t *testing.T) {
+	for _, s := range []string{"", "v", "2.0.1a", "1..2", "1.2.x", "1.0.0-", "1.0.0-beta..1", "latest", "beta1", "alpha-2", "x1.0", "vv1"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", s)
+		}
+	}
+}